@@ -12,10 +12,27 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
-// FromEnv reads the environment variables and sets the values to the given config.
-func FromEnv(config any, prefix string) {
+// FromEnv reads the environment variables and sets the values to the given config, it returns the error of the first
+// malformed value.
+func FromEnv(config any, prefix string) error {
+	return FromEnvWithTrace(config, prefix, nil)
+}
+
+// FromEnvWithTrace reads the environment variables and sets the values to the given config,
+// the trace function is called with the dotted json path of every field set from the environment.
+func FromEnvWithTrace(config any, prefix string, trace func(path string)) error {
+	return fromEnv(config, prefix, "", trace)
+}
+
+// fromEnv reads the environment variables and sets the values to the given config.
+func fromEnv(config any, prefix, path string, trace func(path string)) (err error) {
 	structureIteration(config, func(fieldStruct reflect.StructField, field reflect.Value, envName string) {
+		if err != nil {
+			return
+		}
+
 		envValue := os.Getenv(prefix + envName)
+		fieldPath := jsonPath(path, fieldStruct)
 
 		if envValue == "" && field.Kind() != reflect.Struct {
 			return
@@ -23,14 +40,19 @@ func FromEnv(config any, prefix string) {
 
 		switch field.Kind() {
 		case reflect.Struct:
-			FromEnv(field.Addr().Interface(), prefix+envName+"_")
+			err = fromEnv(field.Addr().Interface(), prefix+envName+"_", fieldPath, trace)
+			return
 		case reflect.Slice:
 			values := strings.Split(envValue, ",")
 			slice := reflect.MakeSlice(field.Type(), len(values), len(values))
 			for key, value := range values {
-				setFieldValue(slice.Index(key), value)
+				if err = setFieldValue(slice.Index(key), value); err != nil {
+					break
+				}
+			}
+			if err == nil {
+				field.Set(slice)
 			}
-			field.Set(slice)
 		case reflect.Map:
 			m := reflect.MakeMap(field.Type())
 			for _, pair := range strings.Split(envValue, ",") {
@@ -40,9 +62,9 @@ func FromEnv(config any, prefix string) {
 				}
 
 				valueType := fieldStruct.Type.Elem()
-				v, err := typeParser(valueType, kv[1])
-				if err != nil {
-					panic(err)
+				var v any
+				if v, err = typeParser(valueType, kv[1]); err != nil {
+					break
 				}
 
 				keyType := fieldStruct.Type.Key()
@@ -51,11 +73,30 @@ func FromEnv(config any, prefix string) {
 					reflect.ValueOf(v).Convert(valueType),
 				)
 			}
-			field.Set(m)
+			if err == nil {
+				field.Set(m)
+			}
 		default:
-			setFieldValue(field, envValue)
+			if unmarshaler, ok := field.Addr().Interface().(json.Unmarshaler); ok {
+				data, _ := json.Marshal(envValue)
+				err = unmarshaler.UnmarshalJSON(data)
+				break
+			}
+
+			err = setFieldValue(field, envValue)
+		}
+
+		if err != nil {
+			err = fmt.Errorf("invalid value of the environment variable %s: %w", prefix+envName, err)
+			return
+		}
+
+		if trace != nil {
+			trace(fieldPath)
 		}
 	})
+
+	return err
 }
 
 // ToEnv returns an environment struct with the values of the given config.
@@ -141,14 +182,30 @@ func structureIteration(config any, fn func(reflect.StructField, reflect.Value,
 	}
 }
 
+// jsonPath returns the dotted json path of the given field.
+func jsonPath(parent string, fieldStruct reflect.StructField) string {
+	name := strings.Split(fieldStruct.Tag.Get("json"), ",")[0]
+	if name == "" {
+		name = fieldStruct.Name
+	}
+
+	if parent == "" {
+		return name
+	}
+
+	return parent + "." + name
+}
+
 // setFieldValue sets the value of a field to the given value.
-func setFieldValue(field reflect.Value, value string) {
+func setFieldValue(field reflect.Value, value string) error {
 	v, err := kindParser(field.Kind(), value)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	field.Set(reflect.ValueOf(v).Convert(field.Type()))
+
+	return nil
 }
 
 // kindParser returns a function that parses a string to the given kind.
@@ -104,4 +104,14 @@ type (
 		// Close closes the storage and will stop any running garbage collectors and open connections.
 		Close() error
 	}
+
+	// IAtomicKeyValue represents a key-value storage which swaps the values atomically, the replicas sharing it update
+	// the same keys without losing the updates of each other.
+	IAtomicKeyValue interface {
+		IKeyValue
+
+		// CompareAndSwap stores the value for the given key when its current value is old, a nil old value means that
+		// the key does not exist. It returns false without an error when the current value differs.
+		CompareAndSwap(key string, old, val []byte, exp time.Duration) (bool, error)
+	}
 )
@@ -1,6 +1,7 @@
 package event
 
 import (
+	"io"
 	"net/url"
 	"time"
 
@@ -83,28 +84,28 @@ func WithData(value []byte) Option {
 // WithJsonData sets the json data for the event.
 func WithJsonData(value any) Option {
 	return func(o *Options) {
-		o.Data, _ = types.ContentTypeJson.Marshal(value)
+		o.Data = marshal(types.ContentTypeJson, value)
 	}
 }
 
 // WithMsgPackData sets the msgpack data for the event.
 func WithMsgPackData(value any) Option {
 	return func(o *Options) {
-		o.Data, _ = types.ContentTypeMsgPack.Marshal(value)
+		o.Data = marshal(types.ContentTypeMsgPack, value)
 	}
 }
 
 // WithYamlData sets the yaml data for the event.
 func WithYamlData(value any) Option {
 	return func(o *Options) {
-		o.Data, _ = types.ContentTypeYaml.Marshal(value)
+		o.Data = marshal(types.ContentTypeYaml, value)
 	}
 }
 
 // WithFormUrlEncodedData sets the form url encoded data for the event.
 func WithFormUrlEncodedData(value url.Values) Option {
 	return func(o *Options) {
-		o.Data, _ = types.ContentTypeFormUrlEncoded.Marshal(value)
+		o.Data = marshal(types.ContentTypeFormUrlEncoded, value)
 	}
 }
 
@@ -114,3 +115,18 @@ func WithHappen(value types.DateTime) Option {
 		o.Happen = value
 	}
 }
+
+// marshal returns the value marshaled with the content type, the data is empty on failure.
+func marshal(ct types.ContentType, value any) []byte {
+	reader, err := ct.Marshal(value)
+	if err != nil {
+		return []byte{}
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return []byte{}
+	}
+
+	return data
+}
@@ -25,29 +25,37 @@ func NewClient(options *Options) *Client {
 		proxyURL = nethttp.ProxyURL(&options.ProxyURL.URL)
 	}
 
+	var transport nethttp.RoundTripper = &nethttp.Transport{
+		Proxy: proxyURL,
+		DialContext: defaultTransportDialContext(&net.Dialer{
+			Timeout:   options.Timeout,
+			KeepAlive: options.KeepAlive,
+		}),
+		TLSHandshakeTimeout:   options.TLSHandshake,
+		ExpectContinueTimeout: options.ExpectContinue,
+		IdleConnTimeout:       options.IdleConnection,
+		ResponseHeaderTimeout: options.ResponseHeader,
+		MaxIdleConns:          options.MaxIdleConnections,
+		MaxConnsPerHost:       options.MaxConnectionsPerHost,
+		WriteBufferSize:       options.WriteBufferSize,
+		ReadBufferSize:        options.ReadBufferSize,
+		TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
+		ForceAttemptHTTP2:     true,
+	}
+
+	if options.Transport != nil {
+		transport = options.Transport
+	}
+
 	c := &Client{
 		Options: options,
 		client: &nethttp.Client{
-			Transport: &CharsetRoundTripper{
-				tripper: &EncoderRoundTripper{
-					tripper: &LimiterRoundTripper{
-						limiter: rate.NewLimiter(rate.Limit(options.QPS), options.Burst),
-						tripper: &nethttp.Transport{
-							Proxy: proxyURL,
-							DialContext: defaultTransportDialContext(&net.Dialer{
-								Timeout:   options.Timeout,
-								KeepAlive: options.KeepAlive,
-							}),
-							TLSHandshakeTimeout:   options.TLSHandshake,
-							ExpectContinueTimeout: options.ExpectContinue,
-							IdleConnTimeout:       options.IdleConnection,
-							ResponseHeaderTimeout: options.ResponseHeader,
-							MaxIdleConns:          options.MaxIdleConnections,
-							MaxConnsPerHost:       options.MaxConnectionsPerHost,
-							WriteBufferSize:       options.WriteBufferSize,
-							ReadBufferSize:        options.ReadBufferSize,
-							TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
-							ForceAttemptHTTP2:     true,
+			Transport: &TracingRoundTripper{
+				tripper: &CharsetRoundTripper{
+					tripper: &EncoderRoundTripper{
+						tripper: &LimiterRoundTripper{
+							limiter: rate.NewLimiter(rate.Limit(options.QPS), options.Burst),
+							tripper: transport,
 						},
 					},
 				},
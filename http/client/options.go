@@ -154,3 +154,10 @@ func WithBurst(value int) Option {
 		o.Burst = value
 	}
 }
+
+// WithTransport sets the transport which sends the requests, e.g. an in-memory transport in tests.
+func WithTransport(value http.RoundTripper) Option {
+	return func(o *Options) {
+		o.Transport = value
+	}
+}
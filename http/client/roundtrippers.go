@@ -6,9 +6,11 @@ import (
 	"io"
 	"mime"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/andybalholm/brotli"
+	"github.com/leliuga/cdk/tracing"
 	"github.com/mattn/go-encoding"
 	"github.com/pkg/errors"
 )
@@ -104,3 +106,29 @@ func (t *CharsetRoundTripper) RoundTrip(req *http.Request) (response *http.Respo
 
 	return response, err
 }
+
+// RoundTrip executes the HTTP request in a client span of the request context and propagates the trace context.
+func (t *TracingRoundTripper) RoundTrip(req *http.Request) (response *http.Response, err error) {
+	ctx := req.Context()
+	if parent := tracing.SpanFromContext(ctx); parent != nil {
+		var span *tracing.Span
+		ctx, span = parent.Tracer().Start(ctx, req.Method+" "+req.URL.Host, tracing.SpanKindClient)
+		span.SetAttribute("http.method", req.Method)
+		span.SetAttribute("http.url", req.URL.Redacted())
+		defer func() {
+			if response != nil {
+				span.SetAttribute("http.status_code", strconv.Itoa(response.StatusCode))
+				if response.StatusCode >= http.StatusInternalServerError {
+					span.SetError(errors.New(response.Status))
+				}
+			}
+			span.SetError(err)
+			span.End()
+		}()
+	}
+
+	req = req.Clone(ctx)
+	tracing.Inject(ctx, req.Header)
+
+	return t.tripper.RoundTrip(req)
+}
@@ -38,21 +38,22 @@ type (
 
 	// Options is an HTTP client options.
 	Options struct {
-		BaseUri               types.URI      `json:"base_uri"`
-		Headers               nethttp.Header `json:"headers"`
-		ProxyURL              types.URI      `json:"proxy_url"`
-		MaxIdleConnections    int            `json:"max_idle_connections"`
-		MaxConnectionsPerHost int            `json:"max_connections_per_host"`
-		WriteBufferSize       int            `json:"write_buffer_size"`
-		ReadBufferSize        int            `json:"read_buffer_size"`
-		Timeout               time.Duration  `json:"timeout"`
-		KeepAlive             time.Duration  `json:"keep_alive"`
-		TLSHandshake          time.Duration  `json:"tls_handshake"`
-		ExpectContinue        time.Duration  `json:"expect_continue"`
-		IdleConnection        time.Duration  `json:"idle_connection"`
-		ResponseHeader        time.Duration  `json:"response_header"`
-		QPS                   float32        `json:"qps"`
-		Burst                 int            `json:"burst"`
+		BaseUri               types.URI            `json:"base_uri"`
+		Headers               nethttp.Header       `json:"headers"`
+		ProxyURL              types.URI            `json:"proxy_url"`
+		MaxIdleConnections    int                  `json:"max_idle_connections"`
+		MaxConnectionsPerHost int                  `json:"max_connections_per_host"`
+		WriteBufferSize       int                  `json:"write_buffer_size"`
+		ReadBufferSize        int                  `json:"read_buffer_size"`
+		Timeout               time.Duration        `json:"timeout"`
+		KeepAlive             time.Duration        `json:"keep_alive"`
+		TLSHandshake          time.Duration        `json:"tls_handshake"`
+		ExpectContinue        time.Duration        `json:"expect_continue"`
+		IdleConnection        time.Duration        `json:"idle_connection"`
+		ResponseHeader        time.Duration        `json:"response_header"`
+		QPS                   float32              `json:"qps"`
+		Burst                 int                  `json:"burst"`
+		Transport             nethttp.RoundTripper `json:"-"`
 	}
 
 	// LimiterRoundTripper is a tripper that applies rate limiting to requests.
@@ -71,6 +72,11 @@ type (
 		tripper nethttp.RoundTripper
 	}
 
+	// TracingRoundTripper is a tripper that creates a client span and propagates the trace context.
+	TracingRoundTripper struct {
+		tripper nethttp.RoundTripper
+	}
+
 	// Option represents the service option.
 	Option func(o *Options)
 )
@@ -0,0 +1,68 @@
+package openapi
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Version of the OpenAPI specification and the parameter locations
+const (
+	Version = "3.1.0"
+
+	InPath   = "path"
+	InQuery  = "query"
+	InHeader = "header"
+)
+
+var (
+	// ParameterLocations are the struct tags of the fields which are parameters, e.g. `query:"page"`.
+	ParameterLocations = []string{InPath, InQuery, InHeader}
+
+	// PathParameterRegex matches the parameters of a fiber path, e.g. :id, :id? or :id<int>.
+	PathParameterRegex = regexp.MustCompile(`:([A-Za-z0-9_]+)(<[^>]*>)?\??`)
+)
+
+// NewDocument creates a new Document.
+func NewDocument(title, version string) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info: &Info{
+			Title:   title,
+			Version: version,
+		},
+		Paths: map[string]*PathItem{},
+		Components: &Components{
+			Schemas: map[string]*Schema{},
+		},
+		types: map[string]reflect.Type{},
+	}
+}
+
+// AddOperation adds the operation of the method to the path, the parameters of a fiber path are converted to templates.
+func (d *Document) AddOperation(method, path string, operation *Operation) {
+	path = Path(path)
+
+	item, found := d.Paths[path]
+	if !found {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+
+	(*item)[strings.ToLower(method)] = operation
+}
+
+// Path returns the templated path of a fiber path, e.g. /invoices/{id} of /invoices/:id.
+func Path(path string) string {
+	return PathParameterRegex.ReplaceAllString(path, "{$1}")
+}
+
+// PathParameters returns the names of the parameters of a fiber path.
+func PathParameters(path string) []string {
+	var names []string
+	for _, match := range PathParameterRegex.FindAllStringSubmatch(path, -1) {
+		names = append(names, match[1])
+	}
+
+	return names
+}
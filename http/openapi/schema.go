@@ -0,0 +1,272 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/leliuga/cdk/validation"
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	inspectableType     = reflect.TypeOf((*validation.Inspectable)(nil)).Elem()
+	componentsReference = "#/components/schemas/"
+)
+
+// Schema returns the schema of the value, the named structs are added to the components and referenced. The
+// constraints of the fields are derived from the Rules of their struct, see validation.Inspectable.
+func (d *Document) Schema(value any) *Schema {
+	if value == nil {
+		return &Schema{}
+	}
+
+	return d.schemaOf(reflect.TypeOf(value))
+}
+
+// Parameters returns the parameters of the fields of the struct which are tagged by their location, e.g.
+// `path:"id"`, `query:"page"` or `header:"X-Tenant"`. The path parameters are required.
+func (d *Document) Parameters(value any) []*Parameter {
+	t := indirect(reflect.TypeOf(value))
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	constraints := inspect(t)
+
+	var parameters []*Parameter
+	walk(t, func(field reflect.StructField) {
+		for _, in := range ParameterLocations {
+			name := field.Tag.Get(in)
+			if name == "" || name == "-" {
+				continue
+			}
+
+			c := constraints[fieldName(field)]
+			schema := d.schemaOf(field.Type)
+			if indirect(field.Type) == durationType {
+				// The durations of the parameters are parsed from strings, e.g. 1m30s.
+				schema = &Schema{Type: "string", Format: "duration"}
+			}
+			apply(schema, c)
+
+			parameters = append(parameters, &Parameter{
+				Name:     name,
+				In:       in,
+				Required: in == InPath || c != nil && c.Required,
+				Schema:   schema,
+			})
+		}
+	})
+
+	return parameters
+}
+
+// schemaOf returns the schema of the type.
+func (d *Document) schemaOf(t reflect.Type) *Schema {
+	t = indirect(t)
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() != reflect.Struct && t.Kind() != reflect.Map && t.Kind() != reflect.Slice && t.Kind() != reflect.Array &&
+		(t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType)):
+		// The enums marshal their names.
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: 0}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: d.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return d.structSchema(t)
+	}
+
+	return &Schema{}
+}
+
+// structSchema returns the schema of the struct, the named structs are referenced from the components.
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	name := d.componentName(t)
+	if name != "" {
+		if _, found := d.Components.Schemas[name]; found {
+			return &Schema{Ref: componentsReference + name}
+		}
+
+		// The placeholder stops the recursion of the self-referencing structs.
+		d.Components.Schemas[name] = &Schema{}
+	}
+
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	constraints := inspect(t)
+
+	walk(t, func(field reflect.StructField) {
+		for _, in := range ParameterLocations {
+			if field.Tag.Get(in) != "" {
+				return
+			}
+		}
+
+		property, ok := jsonName(field)
+		if !ok {
+			return
+		}
+
+		c := constraints[fieldName(field)]
+		s := d.schemaOf(field.Type)
+		if s.Ref == "" {
+			apply(s, c)
+		}
+
+		schema.Properties[property] = s
+		if c != nil && c.Required {
+			schema.Required = append(schema.Required, property)
+		}
+	})
+
+	if name == "" {
+		return schema
+	}
+
+	d.Components.Schemas[name] = schema
+
+	return &Schema{Ref: componentsReference + name}
+}
+
+// componentName returns the name of the struct in the components, the names of the structs of different packages are
+// qualified by their package when they collide. The anonymous structs are inlined.
+func (d *Document) componentName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		return ""
+	}
+
+	if existing, found := d.types[name]; found && existing != t {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+
+	d.types[name] = t
+
+	return name
+}
+
+// apply applies the constraints of the field to its schema.
+func apply(s *Schema, c *validation.Constraints) {
+	if c == nil {
+		return
+	}
+
+	if c.MinLength > 0 || c.MaxLength > 0 {
+		minLength, maxLength := &c.MinLength, &c.MaxLength
+		if c.MaxLength == 0 {
+			maxLength = nil
+		}
+
+		if s.Type == "array" {
+			s.MinItems, s.MaxItems = minLength, maxLength
+		} else {
+			s.MinLength, s.MaxLength = minLength, maxLength
+		}
+	}
+
+	if c.Min != nil {
+		if c.ExclusiveMin {
+			s.ExclusiveMinimum, s.Minimum = c.Min, nil
+		} else {
+			s.Minimum = c.Min
+		}
+	}
+
+	if c.Max != nil {
+		if c.ExclusiveMax {
+			s.ExclusiveMaximum = c.Max
+		} else {
+			s.Maximum = c.Max
+		}
+	}
+
+	s.Enum = c.Enum
+	s.Pattern = c.Pattern
+}
+
+// inspect returns the constraints of the fields of the struct, the structs which are not inspectable have none.
+func inspect(t reflect.Type) map[string]*validation.Constraints {
+	if reflect.PointerTo(t).Implements(inspectableType) {
+		return validation.Inspect(reflect.New(t).Interface().(validation.Inspectable))
+	}
+
+	return nil
+}
+
+// walk calls fn for the exported fields of the struct, the fields of the embedded structs are walked as well.
+func walk(t reflect.Type, fn func(field reflect.StructField)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && indirect(field.Type).Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			walk(indirect(field.Type), fn)
+			continue
+		}
+
+		if field.IsExported() {
+			fn(field)
+		}
+	}
+}
+
+// jsonName returns the json name of the field, it is false when the field is not marshaled.
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+
+	return field.Name, true
+}
+
+// fieldName returns the name of the field in the validation errors.
+func fieldName(field reflect.StructField) string {
+	if name, ok := jsonName(field); ok {
+		return name
+	}
+
+	return field.Name
+}
+
+// indirect returns the type which the pointer type points to.
+func indirect(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
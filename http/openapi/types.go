@@ -0,0 +1,96 @@
+// Package openapi provides an OpenAPI 3.1 document definition.
+package openapi
+
+import (
+	"reflect"
+)
+
+type (
+	// Document represents an OpenAPI document.
+	Document struct {
+		OpenAPI    string               `json:"openapi"`
+		Info       *Info                `json:"info"`
+		Paths      map[string]*PathItem `json:"paths"`
+		Components *Components          `json:"components,omitempty"`
+		types      map[string]reflect.Type
+	}
+
+	// Info represents the metadata of the API.
+	Info struct {
+		Title       string `json:"title"`
+		Description string `json:"description,omitempty"`
+		Version     string `json:"version"`
+	}
+
+	// PathItem represents the operations of a path by their lower-case methods.
+	PathItem map[string]*Operation
+
+	// Operation represents an operation of a path.
+	Operation struct {
+		OperationID  string               `json:"operationId,omitempty"`
+		Summary      string               `json:"summary,omitempty"`
+		Description  string               `json:"description,omitempty"`
+		Tags         []string             `json:"tags,omitempty"`
+		Deprecated   bool                 `json:"deprecated,omitempty"`
+		ExternalDocs *ExternalDocs        `json:"externalDocs,omitempty"`
+		Parameters   []*Parameter         `json:"parameters,omitempty"`
+		RequestBody  *RequestBody         `json:"requestBody,omitempty"`
+		Responses    map[string]*Response `json:"responses"`
+	}
+
+	// ExternalDocs represents the external documentation of an operation.
+	ExternalDocs struct {
+		URL string `json:"url"`
+	}
+
+	// Parameter represents a path, query or header parameter of an operation.
+	Parameter struct {
+		Name     string  `json:"name"`
+		In       string  `json:"in"`
+		Required bool    `json:"required,omitempty"`
+		Schema   *Schema `json:"schema"`
+	}
+
+	// RequestBody represents the request body of an operation by its content types.
+	RequestBody struct {
+		Required bool                  `json:"required,omitempty"`
+		Content  map[string]*MediaType `json:"content"`
+	}
+
+	// Response represents a response of an operation by its content types.
+	Response struct {
+		Description string                `json:"description"`
+		Content     map[string]*MediaType `json:"content,omitempty"`
+	}
+
+	// MediaType represents the schema of a content type.
+	MediaType struct {
+		Schema *Schema `json:"schema"`
+	}
+
+	// Components represents the reusable schemas of the document.
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas,omitempty"`
+	}
+
+	// Schema represents a JSON Schema of a value.
+	Schema struct {
+		Ref                  string             `json:"$ref,omitempty"`
+		Type                 string             `json:"type,omitempty"`
+		Format               string             `json:"format,omitempty"`
+		Properties           map[string]*Schema `json:"properties,omitempty"`
+		Required             []string           `json:"required,omitempty"`
+		AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+		Items                *Schema            `json:"items,omitempty"`
+		Enum                 []any              `json:"enum,omitempty"`
+		Pattern              string             `json:"pattern,omitempty"`
+		MinLength            *int               `json:"minLength,omitempty"`
+		MaxLength            *int               `json:"maxLength,omitempty"`
+		MinItems             *int               `json:"minItems,omitempty"`
+		MaxItems             *int               `json:"maxItems,omitempty"`
+		Minimum              any                `json:"minimum,omitempty"`
+		Maximum              any                `json:"maximum,omitempty"`
+		ExclusiveMinimum     any                `json:"exclusiveMinimum,omitempty"`
+		ExclusiveMaximum     any                `json:"exclusiveMaximum,omitempty"`
+	}
+)
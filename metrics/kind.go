@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+)
+
+const (
+	KindInvalid Kind = iota //
+	KindCounter
+	KindGauge
+	KindHistogram
+)
+
+var (
+	KindNames = map[Kind]string{
+		KindCounter:   "counter",
+		KindGauge:     "gauge",
+		KindHistogram: "histogram",
+	}
+)
+
+// String outputs the Kind as a string.
+func (k Kind) String() string {
+	return KindNames[k]
+}
+
+// MarshalJSON outputs the Kind as a json.
+func (k Kind) MarshalJSON() ([]byte, error) {
+	if !k.Validate() {
+		return []byte(`""`), nil
+	}
+
+	return []byte(`"` + k.String() + `"`), nil
+}
+
+// UnmarshalJSON parses the Kind from json.
+func (k *Kind) UnmarshalJSON(data []byte) error {
+	str := string(bytes.Trim(data, `"`))
+	if kind := ParseKind(str); kind.Validate() {
+		*k = kind
+	}
+
+	return nil
+}
+
+// Validate returns true if the Kind is valid.
+func (k Kind) Validate() bool {
+	return k != KindInvalid
+}
+
+// ParseKind parses the Kind from string.
+func ParseKind(value string) Kind {
+	value = strings.ToLower(value)
+	for k, v := range KindNames {
+		if v == value {
+			return k
+		}
+	}
+
+	return KindInvalid
+}
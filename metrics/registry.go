@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// ContentType is the content type of the Prometheus text exposition format.
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+var (
+	NameRegex = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+)
+
+// NewRegistry creates a new Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		collectors: map[string]collector{},
+		mutex:      &sync.RWMutex{},
+	}
+}
+
+// Counter returns the counter with the given name, it is registered on first use.
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	return &Counter{r.vector(&Desc{Name: name, Help: help, Kind: KindCounter, Labels: labels}, nil)}
+}
+
+// Gauge returns the gauge with the given name, it is registered on first use.
+func (r *Registry) Gauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{r.vector(&Desc{Name: name, Help: help, Kind: KindGauge, Labels: labels}, nil)}
+}
+
+// Histogram returns the histogram with the given name and buckets, it is registered on first use.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	return &Histogram{r.vector(&Desc{Name: name, Help: help, Kind: KindHistogram, Labels: labels}, buckets)}
+}
+
+// GaugeFunc registers a gauge whose value is computed by the given function when collected.
+func (r *Registry) GaugeFunc(name, help string, fn func() float64) {
+	r.register(&function{desc: &Desc{Name: name, Help: help, Kind: KindGauge}, fn: fn})
+}
+
+// CounterFunc registers a counter whose value is computed by the given function when collected.
+func (r *Registry) CounterFunc(name, help string, fn func() float64) {
+	r.register(&function{desc: &Desc{Name: name, Help: help, Kind: KindCounter}, fn: fn})
+}
+
+// Unregister removes the metric with the given name.
+func (r *Registry) Unregister(name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.collectors, name)
+}
+
+// Gather collects all registered metrics sorted by name.
+func (r *Registry) Gather() []*Family {
+	r.mutex.RLock()
+	collectors := make([]collector, 0, len(r.collectors))
+	for _, c := range r.collectors {
+		collectors = append(collectors, c)
+	}
+	r.mutex.RUnlock()
+
+	families := make([]*Family, 0, len(collectors))
+	for _, c := range collectors {
+		families = append(families, &Family{Desc: c.describe(), Samples: c.collect()})
+	}
+
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].Name < families[j].Name
+	})
+
+	return families
+}
+
+// Write writes all registered metrics in the Prometheus text exposition format.
+func (r *Registry) Write(w io.Writer) error {
+	buf := bufio.NewWriter(w)
+
+	for _, family := range r.Gather() {
+		if family.Help != "" {
+			buf.WriteString("# HELP " + family.Name + " " + escape(family.Help, false) + "\n")
+		}
+		buf.WriteString("# TYPE " + family.Name + " " + family.Kind.String() + "\n")
+
+		for _, sample := range family.Samples {
+			buf.WriteString(sample.Name)
+			if len(sample.Labels) > 0 {
+				pairs := make([]string, 0, len(sample.Labels))
+				for _, label := range sample.Labels {
+					pairs = append(pairs, label.Name+`="`+escape(label.Value, true)+`"`)
+				}
+				buf.WriteString("{" + strings.Join(pairs, ",") + "}")
+			}
+			buf.WriteString(" " + formatFloat(sample.Value) + "\n")
+		}
+	}
+
+	return buf.Flush()
+}
+
+// vector returns the registered vector with the given description, it panics when the name is invalid
+// or already registered with another kind or labels.
+func (r *Registry) vector(desc *Desc, buckets []float64) *vector {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if existing, ok := r.collectors[desc.Name]; ok {
+		v, isVector := existing.(*vector)
+		if !isVector || v.desc.Kind != desc.Kind || strings.Join(v.desc.Labels, ",") != strings.Join(desc.Labels, ",") {
+			panic(fmt.Sprintf("metrics: %s is already registered as a different metric", desc.Name))
+		}
+
+		return v
+	}
+
+	validate(desc)
+	v := &vector{
+		desc:    desc,
+		buckets: buckets,
+		series:  map[string]*series{},
+		mutex:   &sync.Mutex{},
+	}
+	r.collectors[desc.Name] = v
+
+	return v
+}
+
+// register registers the collector, it panics when the name is invalid or already registered.
+func (r *Registry) register(c collector) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	desc := c.describe()
+	if _, ok := r.collectors[desc.Name]; ok {
+		panic(fmt.Sprintf("metrics: %s is already registered", desc.Name))
+	}
+
+	validate(desc)
+	r.collectors[desc.Name] = c
+}
+
+// validate panics when the metric or label names are invalid.
+func validate(desc *Desc) {
+	if !NameRegex.MatchString(desc.Name) {
+		panic(fmt.Sprintf("metrics: %q is not a valid metric name", desc.Name))
+	}
+
+	for _, label := range desc.Labels {
+		if !NameRegex.MatchString(label) || strings.HasPrefix(label, "__") {
+			panic(fmt.Sprintf("metrics: %q is not a valid label name of %s", label, desc.Name))
+		}
+	}
+}
+
+// escape escapes the help text or the label value.
+func escape(value string, quote bool) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	if quote {
+		value = strings.ReplaceAll(value, `"`, `\"`)
+	}
+
+	return value
+}
+
+// formatFloat formats the value as the exposition format expects.
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
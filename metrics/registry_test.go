@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryWrite(t *testing.T) {
+	r := NewRegistry()
+
+	requests := r.Counter("requests_total", "Total number of requests.", "method", "status")
+	requests.Inc("GET", "200")
+	requests.Add(2, "GET", "200")
+	requests.Inc("POST", "500")
+	assert.Same(t, requests.vector, r.Counter("requests_total", "", "method", "status").vector)
+	assert.Panics(t, func() { requests.Add(-1, "GET", "200") })
+	assert.Panics(t, func() { requests.Inc("GET") })
+	assert.Panics(t, func() { r.Gauge("requests_total", "") })
+
+	r.Gauge("temperature", "Current \"temperature\".", "room").Set(21.5, "a\"b")
+	r.Histogram("duration_seconds", "Duration.", []float64{0.1, 1}).Observe(0.5)
+	r.GaugeFunc("answer", "", func() float64 { return 42 })
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, r.Write(buf))
+	assert.Equal(t, `# TYPE answer gauge
+answer 42
+# HELP duration_seconds Duration.
+# TYPE duration_seconds histogram
+duration_seconds_bucket{le="0.1"} 0
+duration_seconds_bucket{le="1"} 1
+duration_seconds_bucket{le="+Inf"} 1
+duration_seconds_sum 0.5
+duration_seconds_count 1
+# HELP requests_total Total number of requests.
+# TYPE requests_total counter
+requests_total{method="GET",status="200"} 3
+requests_total{method="POST",status="500"} 1
+# HELP temperature Current "temperature".
+# TYPE temperature gauge
+temperature{room="a\"b"} 21.5
+`, buf.String())
+}
+
+func TestRegisterRuntimeMetrics(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterRuntimeMetrics()
+
+	names := map[string]bool{}
+	for _, family := range r.Gather() {
+		names[family.Name] = true
+	}
+
+	assert.True(t, names["go_goroutines"])
+	assert.True(t, names["go_memstats_alloc_bytes"])
+	assert.True(t, names["process_start_time_seconds"])
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// RegisterRuntimeMetrics registers the Go runtime and process metrics.
+func (r *Registry) RegisterRuntimeMetrics() {
+	started := float64(time.Now().Unix())
+	m := &memStats{mutex: &sync.Mutex{}}
+
+	r.GaugeFunc("go_goroutines", "Number of goroutines that currently exist.", func() float64 {
+		return float64(runtime.NumGoroutine())
+	})
+	r.GaugeFunc("go_threads", "Number of OS threads created.", func() float64 {
+		n, _ := runtime.ThreadCreateProfile(nil)
+		return float64(n)
+	})
+	r.GaugeFunc("go_memstats_alloc_bytes", "Number of bytes allocated and still in use.", func() float64 {
+		return float64(m.read().Alloc)
+	})
+	r.CounterFunc("go_memstats_alloc_bytes_total", "Total number of bytes allocated, even if freed.", func() float64 {
+		return float64(m.read().TotalAlloc)
+	})
+	r.GaugeFunc("go_memstats_sys_bytes", "Number of bytes obtained from system.", func() float64 {
+		return float64(m.read().Sys)
+	})
+	r.GaugeFunc("go_memstats_heap_alloc_bytes", "Number of heap bytes allocated and still in use.", func() float64 {
+		return float64(m.read().HeapAlloc)
+	})
+	r.GaugeFunc("go_memstats_heap_inuse_bytes", "Number of heap bytes that are in use.", func() float64 {
+		return float64(m.read().HeapInuse)
+	})
+	r.GaugeFunc("go_memstats_heap_objects", "Number of allocated objects.", func() float64 {
+		return float64(m.read().HeapObjects)
+	})
+	r.GaugeFunc("go_memstats_stack_inuse_bytes", "Number of bytes in use by the stack allocator.", func() float64 {
+		return float64(m.read().StackInuse)
+	})
+	r.CounterFunc("go_memstats_gc_cycles_total", "Number of completed GC cycles.", func() float64 {
+		return float64(m.read().NumGC)
+	})
+	r.GaugeFunc("go_memstats_last_gc_time_seconds", "Number of seconds since 1970 of last garbage collection.", func() float64 {
+		return float64(m.read().LastGC) / 1e9
+	})
+	r.Gauge("go_info", "Information about the Go environment.", "version").Set(1, runtime.Version())
+	r.GaugeFunc("process_start_time_seconds", "Start time of the process since unix epoch in seconds.", func() float64 {
+		return started
+	})
+}
+
+// read returns the memory statistics, they are refreshed at most once per second.
+func (m *memStats) read() runtime.MemStats {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if time.Since(m.updated) > time.Second {
+		runtime.ReadMemStats(&m.stats)
+		m.updated = time.Now()
+	}
+
+	return m.stats
+}
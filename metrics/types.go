@@ -0,0 +1,96 @@
+// Package metrics provides a metrics registry with the Prometheus text exposition format.
+package metrics
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+type (
+	// Registry holds the registered metrics.
+	Registry struct {
+		collectors map[string]collector
+		mutex      *sync.RWMutex
+	}
+
+	// Desc describes a metric.
+	Desc struct {
+		Name   string   `json:"name"`
+		Help   string   `json:"help"`
+		Kind   Kind     `json:"kind"`
+		Labels []string `json:"labels"`
+	}
+
+	// Family represents a metric with its collected samples.
+	Family struct {
+		*Desc   `json:",inline"`
+		Samples []*Sample `json:"samples"`
+	}
+
+	// Sample represents a single collected value.
+	Sample struct {
+		Name   string   `json:"name"`
+		Labels []*Label `json:"labels"`
+		Value  float64  `json:"value"`
+	}
+
+	// Label represents a label pair of a sample.
+	Label struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+
+	// Counter represents a monotonically increasing metric partitioned by labels.
+	Counter struct {
+		*vector
+	}
+
+	// Gauge represents a metric which can go up and down partitioned by labels.
+	Gauge struct {
+		*vector
+	}
+
+	// Histogram represents a metric which counts observations in buckets partitioned by labels.
+	Histogram struct {
+		*vector
+	}
+
+	// vector holds the series of a metric keyed by the label values.
+	vector struct {
+		desc    *Desc
+		buckets []float64
+		series  map[string]*series
+		mutex   *sync.Mutex
+	}
+
+	// series represents the state of a single label combination.
+	series struct {
+		labels []string
+		value  float64
+		counts []uint64
+		count  uint64
+	}
+
+	// function represents a metric whose value is computed when collected.
+	function struct {
+		desc *Desc
+		fn   func() float64
+	}
+
+	// memStats caches the runtime memory statistics between collections.
+	memStats struct {
+		stats   runtime.MemStats
+		updated time.Time
+		mutex   *sync.Mutex
+	}
+
+	// collector represents a metric which can be collected.
+	collector interface {
+		describe() *Desc
+		collect() []*Sample
+	}
+
+	// Kind represents the metric type.
+	Kind uint8
+)
@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// DefaultBuckets are the default histogram buckets, tailored to measure request latency in seconds.
+	DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+)
+
+// Inc increments the counter of the given label values by 1.
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add adds the given value to the counter of the given label values, it panics when the value is negative.
+func (c *Counter) Add(value float64, values ...string) {
+	if value < 0 {
+		panic(fmt.Sprintf("metrics: counter %s cannot decrease", c.desc.Name))
+	}
+
+	c.update(values, func(s *series) { s.value += value })
+}
+
+// Set sets the gauge of the given label values.
+func (g *Gauge) Set(value float64, values ...string) {
+	g.update(values, func(s *series) { s.value = value })
+}
+
+// Inc increments the gauge of the given label values by 1.
+func (g *Gauge) Inc(values ...string) {
+	g.Add(1, values...)
+}
+
+// Dec decrements the gauge of the given label values by 1.
+func (g *Gauge) Dec(values ...string) {
+	g.Add(-1, values...)
+}
+
+// Add adds the given value to the gauge of the given label values.
+func (g *Gauge) Add(value float64, values ...string) {
+	g.update(values, func(s *series) { s.value += value })
+}
+
+// Observe adds an observation to the histogram of the given label values.
+func (h *Histogram) Observe(value float64, values ...string) {
+	h.update(values, func(s *series) {
+		for i, bound := range h.buckets {
+			if value <= bound {
+				s.counts[i]++
+			}
+		}
+		s.value += value
+		s.count++
+	})
+}
+
+// update applies the function to the series of the given label values, the series is created on first use.
+func (v *vector) update(values []string, fn func(s *series)) {
+	if len(values) != len(v.desc.Labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", v.desc.Name, len(v.desc.Labels), len(values)))
+	}
+
+	key := strings.Join(values, "\xff")
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	s, ok := v.series[key]
+	if !ok {
+		s = &series{
+			labels: append([]string(nil), values...),
+			counts: make([]uint64, len(v.buckets)),
+		}
+		v.series[key] = s
+	}
+
+	fn(s)
+}
+
+// describe returns the description of the vector.
+func (v *vector) describe() *Desc {
+	return v.desc
+}
+
+// collect returns the samples of all series sorted by the label values.
+func (v *vector) collect() []*Sample {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	keys := make([]string, 0, len(v.series))
+	for key := range v.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var samples []*Sample
+	for _, key := range keys {
+		s := v.series[key]
+		labels := make([]*Label, len(v.desc.Labels))
+		for i, name := range v.desc.Labels {
+			labels[i] = &Label{Name: name, Value: s.labels[i]}
+		}
+
+		if v.desc.Kind != KindHistogram {
+			samples = append(samples, &Sample{Name: v.desc.Name, Labels: labels, Value: s.value})
+			continue
+		}
+
+		for i, bound := range v.buckets {
+			samples = append(samples, &Sample{
+				Name:   v.desc.Name + "_bucket",
+				Labels: append(labels[:len(labels):len(labels)], &Label{Name: "le", Value: formatFloat(bound)}),
+				Value:  float64(s.counts[i]),
+			})
+		}
+
+		samples = append(samples,
+			&Sample{Name: v.desc.Name + "_bucket", Labels: append(labels[:len(labels):len(labels)], &Label{Name: "le", Value: "+Inf"}), Value: float64(s.count)},
+			&Sample{Name: v.desc.Name + "_sum", Labels: labels, Value: s.value},
+			&Sample{Name: v.desc.Name + "_count", Labels: labels, Value: float64(s.count)},
+		)
+	}
+
+	return samples
+}
+
+// describe returns the description of the function.
+func (f *function) describe() *Desc {
+	return f.desc
+}
+
+// collect returns the computed sample of the function.
+func (f *function) collect() []*Sample {
+	return []*Sample{{Name: f.desc.Name, Value: f.fn()}}
+}
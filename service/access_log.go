@@ -0,0 +1,45 @@
+package service
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/service/middleware/accesslog"
+	"github.com/leliuga/cdk/validation"
+)
+
+// Default values for the Service access log
+const (
+	DefaultAccessLogEnabled    = true
+	DefaultAccessLogSampleRate = 1.0
+)
+
+// NewAccessLog creates a new AccessLog.
+func NewAccessLog() *AccessLog {
+	return &AccessLog{
+		Enabled:    DefaultAccessLogEnabled,
+		SampleRate: DefaultAccessLogSampleRate,
+		Exclude:    []string{DefaultPathMonitoring},
+		JSON:       false,
+	}
+}
+
+// Handler returns the access log middleware, it passes the requests through when the access log is disabled.
+func (a *AccessLog) Handler() fiber.Handler {
+	if a == nil || !a.Enabled {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return accesslog.New(accesslog.Config{
+		Exclude:    a.Exclude,
+		SampleRate: a.SampleRate,
+		JSON:       a.JSON,
+	})
+}
+
+// Validate makes AccessLog validatable by implementing [validation.Validatable] interface.
+func (a *AccessLog) Validate() error {
+	return validation.ValidateStruct(a,
+		validation.Field(&a.SampleRate, validation.Min(0.0), validation.Max(1.0)),
+	)
+}
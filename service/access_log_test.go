@@ -0,0 +1,55 @@
+package service
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/leliuga/cdk/service/middleware/accesslog"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/klog/v2"
+)
+
+func TestAccessLog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	klog.SetLogger(funcr.NewJSON(func(obj string) { buf.WriteString(obj) }, funcr.Options{}))
+	defer klog.ClearLogger()
+
+	s := NewService(NewOptions())
+	s.Use(requestid.New(), accesslog.New(accesslog.Config{
+		Exclude: []string{DefaultPathMonitoring},
+		JSON:    true,
+	}))
+	s.Get("/users/:id", func(c *fiber.Ctx) error {
+		return fiber.ErrNotFound
+	})
+	s.Get(DefaultPathMonitoringLiveness, s.Health.Handler(ProbeLiveness))
+
+	_, err := s.Test(httptest.NewRequest(fiber.MethodGet, DefaultPathMonitoringLiveness, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, buf.Len())
+
+	_, err = s.Test(httptest.NewRequest(fiber.MethodGet, "/users/1", nil))
+	assert.Nil(t, err)
+
+	var line struct {
+		Entry accesslog.Entry `json:"entry"`
+	}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &line))
+
+	entry := line.Entry
+	assert.Equal(t, fiber.MethodGet, entry.Method)
+	assert.Equal(t, "/users/:id", entry.Route)
+	assert.Equal(t, fiber.StatusNotFound, entry.Status)
+	assert.Equal(t, "0.0.0.0", entry.IP)
+	assert.NotEmpty(t, entry.RequestID)
+}
+
+func TestAccessLogValidate(t *testing.T) {
+	assert.Nil(t, NewAccessLog().Validate())
+	assert.NotNil(t, (&AccessLog{SampleRate: 2}).Validate())
+}
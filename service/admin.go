@@ -0,0 +1,37 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MonitoringPort returns the port which serves the monitoring endpoints, the admin port when it is configured.
+func (o *Options) MonitoringPort() int32 {
+	if o.AdminPort > 0 {
+		return o.AdminPort
+	}
+
+	return o.Port
+}
+
+// newAdmin creates the admin app which serves the operational endpoints, it is nil without an admin port.
+func newAdmin(options *Options) *fiber.App {
+	if options.AdminPort <= 0 {
+		return nil
+	}
+
+	return fiber.New(fiber.Config{
+		ServerHeader:          strings.ToLower(options.Name),
+		ReadTimeout:           options.ReadTimeout,
+		WriteTimeout:          options.WriteTimeout,
+		IdleTimeout:           options.IdleTimeout,
+		ErrorHandler:          errorHandler(options),
+		DisableStartupMessage: true,
+		AppName:               options.Name + " admin",
+		JSONEncoder:           json.Marshal,
+		JSONDecoder:           json.Unmarshal,
+		Network:               options.Network,
+	})
+}
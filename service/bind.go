@@ -0,0 +1,280 @@
+package service
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/http"
+	"github.com/leliuga/cdk/types"
+	"github.com/leliuga/cdk/validation"
+)
+
+// Tags of the struct fields which are bound from the request values
+const (
+	BindTagPath   = "path"
+	BindTagQuery  = "query"
+	BindTagHeader = "header"
+	BindTagForm   = "form"
+)
+
+const (
+	// localsBound is the key of the bound value in the locals of the request.
+	localsBound = "service.bound"
+)
+
+var (
+	ErrBindTarget = errors.New("the bind target must be a pointer to a struct")
+
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// NewBindError creates a new BindError.
+func NewBindError(status http.Status, message string, errs validation.Errors) *BindError {
+	return &BindError{
+		Status:  status,
+		Message: message,
+		Errors:  errs,
+	}
+}
+
+// StatusCode returns the HTTP status code.
+func (e *BindError) StatusCode() http.Status {
+	return e.Status
+}
+
+// Error returns the error message.
+func (e *BindError) Error() string {
+	if len(e.Errors) == 0 {
+		return e.Message
+	}
+
+	return e.Message + ": " + e.Errors.Error()
+}
+
+// Bind decodes the request body by its content type into out and binds the path, query and header values into the
+// tagged fields, then validates out. The malformed requests fail with 400 and the invalid ones with 422.
+func Bind(c *fiber.Ctx, out any) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T", ErrBindTarget, out)
+	}
+
+	if err := bindBody(c, out); err != nil {
+		return err
+	}
+
+	errs := validation.Errors{}
+	bindValues(rv.Elem(), BindTagPath, func(name string) []string {
+		if value := c.Params(name); value != "" {
+			return []string{value}
+		}
+
+		return nil
+	}, errs)
+	bindValues(rv.Elem(), BindTagQuery, func(name string) []string {
+		return toStrings(c.Request().URI().QueryArgs().PeekMulti(name))
+	}, errs)
+	bindValues(rv.Elem(), BindTagHeader, func(name string) []string {
+		return toStrings(c.Request().Header.PeekAll(name))
+	}, errs)
+
+	if len(errs) > 0 {
+		return NewBindError(http.StatusBadRequest, "The request is malformed", errs)
+	}
+
+	if err := validation.Validate(out); err != nil {
+		var internal validation.InternalError
+		if errors.As(err, &internal) {
+			return internal.InternalError()
+		}
+
+		var es validation.Errors
+		if errors.As(err, &es) {
+			return NewBindError(http.StatusUnprocessableEntity, "The request is invalid", es)
+		}
+
+		return NewBindError(http.StatusUnprocessableEntity, err.Error(), nil)
+	}
+
+	return nil
+}
+
+// BindHandler returns a fiber handler which binds the request into a new T, the handlers that follow get it by Bound.
+func BindHandler[T any]() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		value := new(T)
+		if err := Bind(c, value); err != nil {
+			return err
+		}
+
+		c.Locals(localsBound, value)
+
+		return c.Next()
+	}
+}
+
+// Bound returns the value bound by BindHandler, it is nil when the request is not bound into a T.
+func Bound[T any](c *fiber.Ctx) *T {
+	value, _ := c.Locals(localsBound).(*T)
+
+	return value
+}
+
+// bindBody decodes the request body into out by the content type of the request.
+func bindBody(c *fiber.Ctx, out any) error {
+	body := c.Body()
+	if len(body) == 0 {
+		return nil
+	}
+
+	switch ct := types.ParseContentType(string(c.Request().Header.ContentType())); ct {
+	case types.ContentTypeJson, types.ContentTypeMsgPack, types.ContentTypeYaml:
+		if err := ct.Unmarshal(bytes.NewReader(body), out); err != nil {
+			return NewBindError(http.StatusBadRequest, "The request body is malformed", nil)
+		}
+	case types.ContentTypeFormUrlEncoded:
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return NewBindError(http.StatusBadRequest, "The request body is malformed", nil)
+		}
+
+		errs := validation.Errors{}
+		bindValues(reflect.ValueOf(out).Elem(), BindTagForm, func(name string) []string {
+			return values[name]
+		}, errs)
+
+		if len(errs) > 0 {
+			return NewBindError(http.StatusBadRequest, "The request body is malformed", errs)
+		}
+	default:
+		return NewBindError(http.StatusUnsupportedMediaType, "The content type of the request body is not supported", nil)
+	}
+
+	return nil
+}
+
+// bindValues sets the fields tagged with the tag to their values, the embedded structs are bound as well. The values
+// which do not fit their fields are added to errs.
+func bindValues(rv reflect.Value, tag string, lookup func(name string) []string, errs validation.Errors) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			bindValues(rv.Field(i), tag, lookup, errs)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Tag.Get(tag)
+		if name == "" || name == "-" {
+			continue
+		}
+
+		values := lookup(name)
+		if len(values) == 0 {
+			continue
+		}
+
+		if err := setField(rv.Field(i), values); err != nil {
+			errs[name] = validation.NewError("validation_bind_invalid", "must be a valid "+field.Type.String())
+		}
+	}
+}
+
+// setField sets the field to the values, the slices get all the values and the others get the first one.
+func setField(v reflect.Value, values []string) error {
+	if v.Kind() == reflect.Ptr {
+		value := reflect.New(v.Type().Elem())
+		if err := setField(value.Elem(), values); err != nil {
+			return err
+		}
+		v.Set(value)
+
+		return nil
+	}
+
+	if v.Kind() == reflect.Slice && !v.Addr().Type().Implements(textUnmarshalerType) {
+		slice := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(slice.Index(i), value); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+
+		return nil
+	}
+
+	return setValue(v, values[0])
+}
+
+// setValue parses the value into v by its kind.
+func setValue(v reflect.Value, value string) error {
+	if v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("a field type %s is not supported", v.Type())
+	}
+
+	return nil
+}
+
+// toStrings returns the values as strings.
+func toStrings(values [][]byte) []string {
+	var s []string
+	for _, value := range values {
+		s = append(s, string(value))
+	}
+
+	return s
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/http"
+	"github.com/leliuga/cdk/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	bindPage struct {
+		Page  int           `query:"page"`
+		Sizes []uint        `query:"size"`
+		Wait  time.Duration `query:"wait"`
+	}
+
+	bindInvoice struct {
+		bindPage
+		ID       string  `json:"id"       path:"id"`
+		Tenant   *string `json:"tenant"   header:"X-Tenant"`
+		Customer string  `json:"customer" form:"customer"`
+		Amount   float64 `json:"amount"   form:"amount"`
+	}
+)
+
+func (i *bindInvoice) Rules() []*validation.FieldRules {
+	return []*validation.FieldRules{
+		validation.Field(&i.Customer, validation.Required, validation.Length(3, 10)),
+		validation.Field(&i.Amount, validation.Min(1.0)),
+	}
+}
+
+func (i bindInvoice) Validate() error {
+	return validation.ValidateStruct(&i, i.Rules()...)
+}
+
+func TestBind(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: errorHandler(NewOptions())})
+	app.Post("/invoices/:id", BindHandler[bindInvoice](), func(c *fiber.Ctx) error {
+		return c.JSON(Bound[bindInvoice](c))
+	})
+
+	tests := []struct {
+		tag         string
+		url         string
+		contentType string
+		body        string
+		status      int
+		response    string
+	}{
+		{"t0", "/invoices/i1?page=2&size=10&size=20&wait=1s", "application/json", `{"customer":"acme","amount":10}`, fiber.StatusOK, `"Page":2,"Sizes":[10,20],"Wait":1000000000,"id":"i1","tenant":"t1","customer":"acme","amount":10`},
+		{"t1", "/invoices/i1", "application/x-www-form-urlencoded", "customer=acme&amount=5", fiber.StatusOK, `"customer":"acme","amount":5`},
+		{"t2", "/invoices/i1", "application/yaml", "customer: acme\namount: 7\n", fiber.StatusOK, `"customer":"acme","amount":7`},
+		{"t3", "/invoices/i1", "application/json", `{"customer":"a","amount":-1}`, fiber.StatusUnprocessableEntity, `"errors":{"amount":"must be no less than 1","customer":"the length must be between 3 and 10"}`},
+		{"t4", "/invoices/i1", "application/json", `{"customer":`, fiber.StatusBadRequest, `"detail":"The request body is malformed"`},
+		{"t5", "/invoices/i1?page=two", "application/json", `{"customer":"acme","amount":10}`, fiber.StatusBadRequest, `"errors":{"page":"must be a valid int"}`},
+		{"t6", "/invoices/i1", "text/plain", "acme", fiber.StatusUnsupportedMediaType, `"status":415`},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(fiber.MethodPost, test.url, strings.NewReader(test.body))
+		req.Header.Set(fiber.HeaderContentType, test.contentType)
+		req.Header.Set("X-Tenant", "t1")
+
+		res, err := app.Test(req)
+		assert.Nil(t, err, test.tag)
+		body, _ := io.ReadAll(res.Body)
+		assert.Equal(t, test.status, res.StatusCode, test.tag)
+		assert.Contains(t, string(body), test.response, test.tag)
+	}
+
+	var e http.IError = NewBindError(http.StatusBadRequest, "The request is malformed", nil)
+	b, _ := json.Marshal(e)
+	assert.Equal(t, `{"status":"Bad Request","message":"The request is malformed"}`, string(b))
+	assert.ErrorIs(t, Bind(nil, bindInvoice{}), ErrBindTarget)
+}
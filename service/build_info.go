@@ -3,8 +3,10 @@ package service
 import (
 	"regexp"
 	"runtime"
+	"runtime/debug"
 	"strings"
 
+	"github.com/leliuga/cdk/types"
 	"github.com/leliuga/cdk/validation"
 )
 
@@ -13,6 +15,7 @@ var (
 	PlatformRegex     = regexp.MustCompile(`^(linux\/(amd64|arm64))$`)
 	ArchitectureRegex = regexp.MustCompile(`^(amd64|arm64)$`)
 	OSRegex           = regexp.MustCompile(`^linux$`)
+	MajorVersionRegex = regexp.MustCompile(`/v[0-9]+$`)
 )
 
 const (
@@ -22,9 +25,12 @@ const (
 	InvalidArchitecture = "An architecture must be a valid, supported architectures are: amd64, arm64."
 )
 
-// NewBuildInfo creates a new BuildInfo.
+// readBuildInfo reads the build information embedded in the binary, it is replaced in tests.
+var readBuildInfo = debug.ReadBuildInfo
+
+// NewBuildInfo creates a new BuildInfo, the empty values fall back to the Go module build information.
 func NewBuildInfo(repository, commit, when string) *BuildInfo {
-	return &BuildInfo{
+	b := &BuildInfo{
 		Repository:   repository,
 		Commit:       commit,
 		When:         when,
@@ -32,7 +38,15 @@ func NewBuildInfo(repository, commit, when string) *BuildInfo {
 		Platform:     runtime.GOOS + "/" + runtime.GOARCH,
 		OS:           runtime.GOOS,
 		Architecture: runtime.GOARCH,
+		Dependencies: []*BuildDependency{},
+		Settings:     types.NewMap[string](),
+	}
+
+	if info, ok := readBuildInfo(); ok {
+		b.merge(info)
 	}
+
+	return b
 }
 
 // Validate returns true if the BuildInfo is valid.
@@ -47,3 +61,51 @@ func (b *BuildInfo) Validate() error {
 		validation.Field(&b.OS, validation.Required, validation.Match(OSRegex).Error(InvalidOS)),
 	)
 }
+
+// merge fills the empty values from the Go module build information and records the dependencies and settings.
+func (b *BuildInfo) merge(info *debug.BuildInfo) {
+	for _, setting := range info.Settings {
+		b.Settings.Set(setting.Key, setting.Value)
+	}
+
+	if b.Repository == "" {
+		b.Repository = repositoryURL(info.Main.Path)
+	}
+
+	if b.Commit == "" {
+		b.Commit = b.Settings.Get("vcs.revision")
+	}
+
+	b.Modified = b.Modified || b.Settings.Get("vcs.modified") == "true"
+
+	if b.When == "" {
+		b.When = b.Settings.Get("vcs.time")
+	}
+
+	for _, dependency := range info.Deps {
+		d := &BuildDependency{
+			Path:    dependency.Path,
+			Version: dependency.Version,
+			Sum:     dependency.Sum,
+		}
+
+		if dependency.Replace != nil {
+			d.Replace = dependency.Replace.Path
+			if dependency.Replace.Version != "" {
+				d.Replace += "@" + dependency.Replace.Version
+			}
+		}
+
+		b.Dependencies = append(b.Dependencies, d)
+	}
+}
+
+// repositoryURL returns the url of the repository of the given module path, e.g. github.com/leliuga/cdk/v2 is
+// https://github.com/leliuga/cdk.
+func repositoryURL(module string) string {
+	if i := strings.Index(module, "/"); i < 0 || !strings.Contains(module[:i], ".") {
+		return ""
+	}
+
+	return "https://" + MajorVersionRegex.ReplaceAllString(module, "")
+}
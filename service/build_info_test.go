@@ -0,0 +1,52 @@
+package service
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBuildInfo(t *testing.T) {
+	read := readBuildInfo
+	defer func() { readBuildInfo = read }()
+
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			Main: debug.Module{Path: "github.com/leliuga/billing/v2"},
+			Deps: []*debug.Module{
+				{Path: "github.com/gofiber/fiber/v2", Version: "v2.52.0", Sum: "h1:abc="},
+				{Path: "github.com/leliuga/cdk", Version: "v0.1.0", Replace: &debug.Module{Path: "../cdk"}},
+			},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "a6b3404c5b5f0d1e2f3a4b5c6d7e8f9a0b1c2d3e"},
+				{Key: "vcs.time", Value: "2024-01-02T15:04:05Z"},
+				{Key: "vcs.modified", Value: "true"},
+			},
+		}, true
+	}
+
+	tests := []struct {
+		tag        string
+		buildInfo  *BuildInfo
+		repository string
+		commit     string
+		modified   bool
+	}{
+		{"t0", NewBuildInfo("", "", ""), "https://github.com/leliuga/billing", "a6b3404c5b5f0d1e2f3a4b5c6d7e8f9a0b1c2d3e", true},
+		{"t1", NewBuildInfo("https://gitlab.com/leliuga/billing", "b7c4515", "now"), "https://gitlab.com/leliuga/billing", "b7c4515", true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.repository, test.buildInfo.Repository, test.tag)
+		assert.Equal(t, test.commit, test.buildInfo.Commit, test.tag)
+		assert.Equal(t, test.modified, test.buildInfo.Modified, test.tag)
+		assert.Len(t, test.buildInfo.Dependencies, 2, test.tag)
+		assert.Equal(t, "2024-01-02T15:04:05Z", test.buildInfo.Settings.Get("vcs.time"), test.tag)
+	}
+
+	b := NewBuildInfo("", "", "")
+	assert.Equal(t, "2024-01-02T15:04:05Z", b.When)
+	assert.Equal(t, "../cdk", b.Dependencies[1].Replace)
+	assert.Equal(t, "", repositoryURL("command-line-arguments"))
+}
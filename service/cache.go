@@ -0,0 +1,90 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/storage/memory"
+	"github.com/leliuga/cdk/service/middleware"
+	"github.com/leliuga/cdk/service/middleware/cache"
+	"github.com/leliuga/cdk/types"
+	"github.com/leliuga/cdk/validation"
+)
+
+// Default values for the Service cache
+const (
+	DefaultCacheEnabled    = false
+	DefaultCacheExpiration = 1 * time.Minute
+)
+
+// NewCache creates a new Cache.
+func NewCache() *Cache {
+	return &Cache{
+		Enabled:    DefaultCacheEnabled,
+		Expiration: DefaultCacheExpiration,
+		Exclude:    []string{DefaultPathMonitoring},
+		Routes:     types.NewMap[time.Duration](),
+	}
+}
+
+// Handler returns the cache middleware, it passes the requests through when the cache is disabled. The expiration of
+// the route with the longest path prefix of the request applies, see middleware.HasPathPrefix, the others use the
+// default expiration.
+func (c *Cache) Handler() fiber.Handler {
+	if c == nil || !c.Enabled {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	if c.Storage == nil {
+		c.Storage = memory.New()
+	}
+
+	routes := c.Routes.Keys()
+
+	// The longest prefixes are matched first.
+	sort.Slice(routes, func(i, j int) bool {
+		return len(routes[i]) > len(routes[j])
+	})
+
+	return cache.New(cache.Config{
+		Next: func(ctx *fiber.Ctx) bool {
+			for _, prefix := range c.Exclude {
+				if middleware.HasPathPrefix(ctx.Path(), prefix) {
+					return true
+				}
+			}
+
+			return false
+		},
+		Expiration: c.Expiration,
+		ExpirationGenerator: func(ctx *fiber.Ctx) time.Duration {
+			for _, route := range routes {
+				if middleware.HasPathPrefix(ctx.Path(), route) {
+					return c.Routes.Get(route)
+				}
+			}
+
+			return c.Expiration
+		},
+		Storage: c.Storage,
+	})
+}
+
+// Invalidate removes the cached responses which are tagged with any of the given tags, see cache.Tag.
+func (c *Cache) Invalidate(tags ...string) error {
+	if c == nil || c.Storage == nil {
+		return nil
+	}
+
+	return cache.Invalidate(c.Storage, cache.ConfigDefault.Prefix, tags...)
+}
+
+// Validate makes Cache validatable by implementing [validation.Validatable] interface.
+func (c *Cache) Validate() error {
+	return validation.ValidateStruct(c,
+		validation.Field(&c.Expiration, validation.When(c.Enabled, validation.Required, validation.Min(time.Second))),
+	)
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/etag"
+	"github.com/leliuga/cdk/service/middleware/cache"
+	"github.com/leliuga/cdk/service/middleware/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	calls := 0
+	c := NewCache()
+	c.Enabled = true
+	c.Routes.Set("/live", 0)
+	assert.Nil(t, c.Validate())
+
+	app := fiber.New()
+	app.Use(etag.New(), c.Handler())
+	app.Get("/invoices", func(ctx *fiber.Ctx) error {
+		calls++
+		cache.Tag(ctx, "invoices")
+		ctx.Set(fiber.HeaderVary, fiber.HeaderAcceptLanguage)
+
+		return ctx.SendString(ctx.Get(fiber.HeaderAcceptLanguage) + " " + strconv.Itoa(calls))
+	})
+	app.Get("/private", func(ctx *fiber.Ctx) error {
+		calls++
+		ctx.Set(fiber.HeaderCacheControl, "private")
+
+		return ctx.SendString(strconv.Itoa(calls))
+	})
+	app.Get("/profile", func(ctx *fiber.Ctx) error {
+		calls++
+
+		return ctx.SendString(ctx.Get(fiber.HeaderAuthorization) + " " + strconv.Itoa(calls))
+	})
+	app.Get("/catalog", func(ctx *fiber.Ctx) error {
+		calls++
+		ctx.Set(fiber.HeaderCacheControl, "public")
+
+		return ctx.SendString(strconv.Itoa(calls))
+	})
+	app.Get("/live", func(ctx *fiber.Ctx) error {
+		calls++
+
+		return ctx.SendString(strconv.Itoa(calls))
+	})
+
+	request := func(path string, headers map[string]string) (string, string) {
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+
+		res, err := app.Test(req)
+		assert.Nil(t, err)
+		body, _ := io.ReadAll(res.Body)
+
+		return string(body), res.Header.Get(cache.HeaderCacheStatus)
+	}
+
+	tests := []struct {
+		tag     string
+		path    string
+		headers map[string]string
+		body    string
+		status  string
+	}{
+		{"t0", "/invoices", map[string]string{fiber.HeaderAcceptLanguage: "en"}, "en 1", "miss"},
+		{"t1", "/invoices", map[string]string{fiber.HeaderAcceptLanguage: "en"}, "en 1", "hit"},
+		{"t2", "/invoices", map[string]string{fiber.HeaderAcceptLanguage: "lt"}, "lt 2", "miss"},
+		{"t3", "/invoices", map[string]string{fiber.HeaderAcceptLanguage: "en"}, "en 1", "hit"},
+		{"t4", "/invoices", map[string]string{fiber.HeaderAcceptLanguage: "en", fiber.HeaderCacheControl: "no-cache"}, "en 3", "miss"},
+		{"t5", "/private", nil, "4", ""},
+		{"t6", "/private", nil, "5", ""},
+		{"t7", "/live", nil, "6", ""},
+		{"t8", "/live", nil, "7", ""},
+		{"t9", "/profile", map[string]string{fiber.HeaderAuthorization: "Bearer a"}, "Bearer a 8", ""},
+		{"t10", "/profile", map[string]string{fiber.HeaderAuthorization: "Bearer b"}, "Bearer b 9", ""},
+		{"t11", "/profile", nil, " 10", "miss"},
+		{"t12", "/profile", map[string]string{fiber.HeaderAuthorization: "Bearer a"}, "Bearer a 11", ""},
+		{"t13", "/profile", map[string]string{fiber.HeaderCookie: "session=a"}, " 12", ""},
+		{"t14", "/catalog", map[string]string{fiber.HeaderAuthorization: "Bearer a"}, "13", "miss"},
+		{"t15", "/catalog", nil, "13", "hit"},
+	}
+
+	for _, test := range tests {
+		body, status := request(test.path, test.headers)
+		assert.Equal(t, test.body, body, test.tag)
+		assert.Equal(t, test.status, status, test.tag)
+	}
+
+	req := httptest.NewRequest(fiber.MethodGet, "/invoices", nil)
+	req.Header.Set(fiber.HeaderAcceptLanguage, "en")
+	res, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, "hit", res.Header.Get(cache.HeaderCacheStatus))
+
+	req.Header.Set(fiber.HeaderIfNoneMatch, res.Header.Get(fiber.HeaderETag))
+	res, err = app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusNotModified, res.StatusCode)
+
+	assert.Nil(t, c.Invalidate("invoices"))
+	body, status := request("/invoices", map[string]string{fiber.HeaderAcceptLanguage: "en"})
+	assert.Equal(t, "en 14", body)
+	assert.Equal(t, "miss", status)
+
+	c.Expiration = time.Millisecond
+	assert.NotNil(t, c.Validate())
+}
+
+func TestCacheRequestHeaders(t *testing.T) {
+	requests := 0
+	c := NewCache()
+	c.Enabled = true
+	assert.Nil(t, c.Validate())
+
+	app := fiber.New()
+	app.Use(func(ctx *fiber.Ctx) error {
+		requests++
+		ctx.Set(fiber.HeaderXRequestID, strconv.Itoa(requests))
+		ctx.Set(ratelimit.HeaderRemaining, strconv.Itoa(100-requests))
+
+		return ctx.Next()
+	}, c.Handler())
+	app.Get("/catalog", func(ctx *fiber.Ctx) error {
+		ctx.Set(fiber.HeaderCacheControl, "public")
+
+		return ctx.SendString("catalog")
+	})
+
+	for i, status := range []string{"miss", "hit"} {
+		res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/catalog", nil))
+		assert.Nil(t, err)
+		assert.Equal(t, status, res.Header.Get(cache.HeaderCacheStatus))
+		assert.Equal(t, strconv.Itoa(i+1), res.Header.Get(fiber.HeaderXRequestID))
+		assert.Equal(t, strconv.Itoa(99-i), res.Header.Get(ratelimit.HeaderRemaining))
+	}
+}
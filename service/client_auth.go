@@ -0,0 +1,75 @@
+package service
+
+import (
+	"bytes"
+	"crypto/tls"
+	"strings"
+)
+
+const (
+	ClientAuthInvalid ClientAuth = iota //
+	ClientAuthNone
+	ClientAuthRequest
+	ClientAuthRequire
+)
+
+var (
+	ClientAuthNames = map[ClientAuth]string{
+		ClientAuthNone:    "none",
+		ClientAuthRequest: "request",
+		ClientAuthRequire: "require",
+	}
+)
+
+// String outputs the ClientAuth as a string.
+func (a ClientAuth) String() string {
+	return ClientAuthNames[a]
+}
+
+// MarshalJSON outputs the ClientAuth as a json.
+func (a ClientAuth) MarshalJSON() ([]byte, error) {
+	if !a.Validate() {
+		return []byte(`""`), nil
+	}
+
+	return []byte(`"` + a.String() + `"`), nil
+}
+
+// UnmarshalJSON parses the ClientAuth from json.
+func (a *ClientAuth) UnmarshalJSON(data []byte) error {
+	str := string(bytes.Trim(data, `"`))
+	if auth := ParseClientAuth(str); auth.Validate() {
+		*a = auth
+	}
+
+	return nil
+}
+
+// Validate returns true if the ClientAuth is valid.
+func (a ClientAuth) Validate() bool {
+	return a != ClientAuthInvalid
+}
+
+// TLS returns the client authentication type of the TLS config, the given certificates are always verified.
+func (a ClientAuth) TLS() tls.ClientAuthType {
+	switch a {
+	case ClientAuthRequest:
+		return tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		return tls.RequireAndVerifyClientCert
+	default:
+		return tls.NoClientCert
+	}
+}
+
+// ParseClientAuth parses the ClientAuth from string.
+func ParseClientAuth(value string) ClientAuth {
+	value = strings.ToLower(value)
+	for k, v := range ClientAuthNames {
+		if v == value {
+			return k
+		}
+	}
+
+	return ClientAuthInvalid
+}
@@ -2,13 +2,25 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"slices"
 	"strings"
 
 	"github.com/leliuga/cdk/service"
 	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
 )
 
-// Execute executes the service.
+// Run executes the service and exits the process with a non-zero code when it fails.
+func Run(svc *service.Service, commands ...*cobra.Command) {
+	if err := Execute(svc, commands...); err != nil {
+		os.Exit(service.ExitCodeFailure)
+	}
+}
+
+// Execute executes the service, the failure is logged and returned. The caller must exit with a non-zero code when
+// it fails, Run does it.
 func Execute(svc *service.Service, commands ...*cobra.Command) error {
 	name := svc.Options.Name
 	cmd := &cobra.Command{
@@ -22,13 +34,40 @@ For help with any of those, simply call them with --help.`,
 		SilenceErrors: true,
 	}
 
+	var flagSet []string
+	cmd.PersistentFlags().StringArrayVar(&flagSet, "set", nil, "Set an option (path=value), it takes precedence over the config files and environment variables"+"``")
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		for _, item := range flagSet {
+			path, value, ok := strings.Cut(item, "=")
+			if !ok {
+				return fmt.Errorf("invalid option %q, the format is path=value", item)
+			}
+
+			if slices.Contains(service.CreationPaths, path) {
+				return fmt.Errorf("invalid option %q, it is read when the service is created and cannot be set by a flag", path)
+			}
+
+			if err := svc.Options.Set(path, value, service.SourceFlag); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
 	cmd.AddCommand(
 		NewInspectCmd(svc.Options),
-		NewMakeCmd(svc.Options),
+		NewMakeCmd(svc.Options, NewMakeOpenAPICmd(svc)),
 		NewServeCmd(svc),
 	)
 	cmd.AddCommand(commands...)
 	cmd.InitDefaultHelpCmd()
 
-	return cmd.Execute()
+	if err := cmd.Execute(); err != nil {
+		klog.ErrorS(err, "the service failed", "name", name)
+
+		return err
+	}
+
+	return nil
 }
@@ -12,7 +12,10 @@ import (
 // NewInspectCmd returns a new inspect command.
 func NewInspectCmd(options *service.Options) *cobra.Command {
 	name := options.Name
-	var flagFormat string
+	var (
+		flagFormat     string
+		flagProvenance bool
+	)
 	cmd := &cobra.Command{
 		Use:     "inspect",
 		Aliases: []string{"i"},
@@ -20,13 +23,22 @@ func NewInspectCmd(options *service.Options) *cobra.Command {
 		Long:    `Inspect a service ` + name,
 		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			var value any = options
+			if flagProvenance {
+				sources, err := options.Sources()
+				if err != nil {
+					return err
+				}
+				value = sources
+			}
+
 			switch flagFormat {
 			case "json":
-				if marshal, err := json.Marshal(&options); err == nil {
+				if marshal, err := json.Marshal(&value); err == nil {
 					fmt.Print(string(marshal) + "\n")
 				}
 			case "yaml":
-				if marshal, err := yaml.MarshalWithOptions(&options, yaml.UseJSONMarshaler()); err == nil {
+				if marshal, err := yaml.MarshalWithOptions(&value, yaml.UseJSONMarshaler()); err == nil {
 					fmt.Print(string(marshal))
 				}
 			}
@@ -35,6 +47,7 @@ func NewInspectCmd(options *service.Options) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&flagFormat, "format", "f", "yaml", "Format (json|yaml)"+"``")
+	cmd.Flags().BoolVarP(&flagProvenance, "provenance", "p", false, "Print the source (default|code|detected|file|overlay|env|flag) of every option")
 
 	return cmd
 }
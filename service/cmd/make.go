@@ -5,14 +5,14 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// NewMakeCmd returns a new make command.
-func NewMakeCmd(options *service.Options) *cobra.Command {
+// NewMakeCmd returns a new make command, the given commands are added to the built-in ones.
+func NewMakeCmd(options *service.Options, commands ...*cobra.Command) *cobra.Command {
 	name := options.Name
 	cmd := &cobra.Command{
 		Use:     "make",
 		Aliases: []string{"m"},
 		Short:   "Make for the service " + name,
-		Long:    `Make a container file, OCI image or manifests for the service ` + name,
+		Long:    `Make a container file, OCI image, manifests or OpenAPI document for the service ` + name,
 		Args:    cobra.NoArgs,
 		RunE:    func(cmd *cobra.Command, args []string) error { return cmd.Usage() },
 	}
@@ -23,6 +23,7 @@ func NewMakeCmd(options *service.Options) *cobra.Command {
 		NewMakeEnvCmd(options),
 		NewMakeOciImageCmd(options),
 	)
+	cmd.AddCommand(commands...)
 
 	return cmd
 }
@@ -66,7 +66,10 @@ func containerFile(options *service.Options) string {
 
 	buf.WriteString(fmt.Sprintf("COPY --from=build /src/bin/%s /usr/bin/%s\n", serviceName, serviceName))
 	buf.WriteString(fmt.Sprintf("EXPOSE %v/tcp\n", options.Port))
-	buf.WriteString(fmt.Sprintf("HEALTHCHECK --start-period=%vs --interval=%vs --timeout=%vs --retries=%v CMD wget --no-verbose --tries=1 --spider 'http://localhost:%v%s' || exit 1\n", probe.InitialDelaySeconds, probe.PeriodSeconds, probe.TimeoutSeconds, probe.FailureThreshold, options.Port, service.DefaultPathMonitoring))
+	if options.AdminPort > 0 {
+		buf.WriteString(fmt.Sprintf("EXPOSE %v/tcp\n", options.AdminPort))
+	}
+	buf.WriteString(fmt.Sprintf("HEALTHCHECK --start-period=%vs --interval=%vs --timeout=%vs --retries=%v CMD wget --no-verbose --tries=1 --spider 'http://localhost:%v%s' || exit 1\n", probe.InitialDelaySeconds, probe.PeriodSeconds, probe.TimeoutSeconds, probe.FailureThreshold, options.MonitoringPort(), service.DefaultPathMonitoring))
 	buf.WriteString(fmt.Sprintf(`CMD ["%s", "serve"]`, serviceName))
 	buf.WriteString("\nSTOPSIGNAL SIGTERM\n")
 
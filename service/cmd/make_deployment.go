@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"math"
 	"path"
 	"strings"
 
@@ -33,6 +34,23 @@ func NewMakeDeploymentCmd(options *service.Options) *cobra.Command {
   Make a deployment manifest for the service ` + options.Name + ` and deploy it to the Docker Swarm cluster
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if options.Environment == service.EnvironmentDevelopment {
+				return fmt.Errorf("the %s environment is not deployable, set the environment to staging or production", options.Environment)
+			}
+
+			if err := options.Validate(); err != nil {
+				return err
+			}
+
+			// The manifests are labeled and tagged with the build info, only the released builds are deployable.
+			if options.BuildInfo == nil {
+				return fmt.Errorf("the service has no build info, build it with the version control information")
+			}
+
+			if err := options.BuildInfo.Validate(); err != nil {
+				return fmt.Errorf("the build info is not deployable: %w", err)
+			}
+
 			switch options.Runtime.Engine {
 			case service.EngineKubernetes:
 				if flagFormat == "terraform" {
@@ -60,12 +78,30 @@ func NewMakeDeploymentCmd(options *service.Options) *cobra.Command {
 
 func kubernetesDeploymentNative(options *service.Options) string {
 	instanceName := fmt.Sprintf("service-%s", strings.ToLower(options.Name))
+	terminationGracePeriodSeconds := int64(math.Ceil((options.ShutdownDelay + options.ShutdownTimeout).Seconds())) + 1
 	servicePortName := "http"
+	adminPortName := "admin"
+	containerPorts := []corev1.ContainerPort{
+		{
+			Name:          servicePortName,
+			ContainerPort: options.Port,
+			Protocol:      corev1.ProtocolTCP,
+		},
+	}
+	if options.AdminPort > 0 {
+		containerPorts = append(containerPorts, corev1.ContainerPort{
+			Name:          adminPortName,
+			ContainerPort: options.AdminPort,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+
 	labelPrefix := strings.ToLower("service." + service.DefaultDomain + "/")
 	labels := types.Map[string]{
 		labelPrefix + "application": strings.ToLower(service.DefaultApplicationName),
 		labelPrefix + "name":        strings.ToLower(options.Name),
 		labelPrefix + "domain":      strings.ToLower(options.Domain),
+		labelPrefix + "environment": options.Environment.String(),
 		labelPrefix + "vendor":      strings.ToLower(service.DefaultVendor),
 		labelPrefix + "repository":  options.BuildInfo.Repository,
 		labelPrefix + "version":     options.BuildInfo.Commit,
@@ -94,7 +130,16 @@ func kubernetesDeploymentNative(options *service.Options) string {
 			Replicas: &options.Runtime.Replicas,
 			Selector: &metav1.LabelSelector{MatchLabels: selectorLabels},
 			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{Name: instanceName, Namespace: options.Runtime.Namespace, Labels: labels},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      instanceName,
+					Namespace: options.Runtime.Namespace,
+					Labels:    labels,
+					Annotations: types.Map[string]{
+						"prometheus.io/scrape": "true",
+						"prometheus.io/port":   fmt.Sprint(options.MonitoringPort()),
+						"prometheus.io/path":   service.DefaultPathMetrics,
+					},
+				},
 				Spec: corev1.PodSpec{
 					Volumes: []corev1.Volume{
 						{
@@ -116,11 +161,19 @@ func kubernetesDeploymentNative(options *service.Options) string {
 						{
 							Name:  instanceName,
 							Image: imageName(options, options.BuildInfo.Commit),
-							Ports: []corev1.ContainerPort{
+							Ports: containerPorts,
+							Env: []corev1.EnvVar{
+								{
+									Name:  service.EnvEnvironment,
+									Value: options.Environment.String(),
+								},
 								{
-									Name:          servicePortName,
-									ContainerPort: options.Port,
-									Protocol:      corev1.ProtocolTCP,
+									Name: service.EnvPodNamespace,
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{
+											FieldPath: "metadata.namespace",
+										},
+									},
 								},
 							},
 							Resources: options.Runtime.ToResourceRequirements(),
@@ -131,7 +184,15 @@ func kubernetesDeploymentNative(options *service.Options) string {
 								},
 							},
 							LivenessProbe: &corev1.Probe{
-								ProbeHandler:        corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: service.DefaultPathMonitoring, Port: intstr.FromInt32(options.Port), Scheme: corev1.URISchemeHTTP}},
+								ProbeHandler:        corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: service.DefaultPathMonitoring, Port: intstr.FromInt32(options.MonitoringPort()), Scheme: corev1.URISchemeHTTP}},
+								InitialDelaySeconds: options.Runtime.Probe.InitialDelaySeconds,
+								TimeoutSeconds:      options.Runtime.Probe.TimeoutSeconds,
+								PeriodSeconds:       options.Runtime.Probe.PeriodSeconds,
+								SuccessThreshold:    options.Runtime.Probe.SuccessThreshold,
+								FailureThreshold:    options.Runtime.Probe.FailureThreshold,
+							},
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler:        corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: service.DefaultPathMonitoringReadiness, Port: intstr.FromInt32(options.MonitoringPort()), Scheme: corev1.URISchemeHTTP}},
 								InitialDelaySeconds: options.Runtime.Probe.InitialDelaySeconds,
 								TimeoutSeconds:      options.Runtime.Probe.TimeoutSeconds,
 								PeriodSeconds:       options.Runtime.Probe.PeriodSeconds,
@@ -142,7 +203,7 @@ func kubernetesDeploymentNative(options *service.Options) string {
 						},
 					},
 					RestartPolicy:                 corev1.RestartPolicyAlways,
-					TerminationGracePeriodSeconds: (*int64)(&options.ShutdownTimeout),
+					TerminationGracePeriodSeconds: &terminationGracePeriodSeconds,
 					ServiceAccountName:            options.Runtime.ServiceAccountName,
 					Hostname:                      instanceName,
 				},
@@ -180,12 +241,14 @@ func dockerSwarmDeploymentNative(options *service.Options) string {
 	maxAttempts := uint64(options.Runtime.Probe.FailureThreshold)
 	limitMemoryBytes, _ := options.Runtime.Resources.Limits.Memory().AsInt64()
 	reservedMemoryBytes, _ := options.Runtime.Resources.Requests.Memory().AsInt64()
+	environment := options.Environment.String()
 
 	labelPrefix := strings.ToLower("service." + service.DefaultDomain + "/")
 	labels := compose.Labels{
 		labelPrefix + "application": strings.ToLower(service.DefaultApplicationName),
 		labelPrefix + "name":        strings.ToLower(options.Name),
 		labelPrefix + "domain":      strings.ToLower(options.Domain),
+		labelPrefix + "environment": options.Environment.String(),
 		labelPrefix + "vendor":      strings.ToLower(service.DefaultVendor),
 		labelPrefix + "repository":  options.BuildInfo.Repository,
 		labelPrefix + "version":     options.BuildInfo.Commit,
@@ -216,7 +279,10 @@ func dockerSwarmDeploymentNative(options *service.Options) string {
 				},
 				Hostname: instanceName,
 				Image:    imageName(options, options.BuildInfo.Commit),
-				Labels:   labels,
+				Environment: compose.MappingWithEquals{
+					service.EnvEnvironment: &environment,
+				},
+				Labels: labels,
 				Logging: &compose.LoggingConfig{
 					Driver: "json-file",
 					Options: map[string]string{
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/goccy/go-json"
+	"github.com/goccy/go-yaml"
+	"github.com/leliuga/cdk/service"
+	"github.com/spf13/cobra"
+)
+
+// NewMakeOpenAPICmd returns a new make openapi command.
+func NewMakeOpenAPICmd(svc *service.Service) *cobra.Command {
+	var flagFormat string
+	cmd := &cobra.Command{
+		Use:     "openapi",
+		Aliases: []string{"o"},
+		Short:   "Make an OpenAPI document",
+		Long:    `Make an OpenAPI document of the routes of the service ` + svc.Options.Name,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// The service is booted without a listener so the routes registered by the components are documented.
+			if err = svc.Boot(cmd.Context()); err != nil {
+				return err
+			}
+			defer func() {
+				err = errors.Join(err, svc.Stop(cmd.Context()))
+			}()
+
+			marshal, err := json.Marshal(svc.OpenAPI())
+			if err != nil {
+				return err
+			}
+
+			switch flagFormat {
+			case "json":
+				fmt.Print(string(marshal) + "\n")
+			case "yaml":
+				if marshal, err = yaml.JSONToYAML(marshal); err != nil {
+					return err
+				}
+				fmt.Print(string(marshal))
+			default:
+				return fmt.Errorf("invalid format %q, the format is json or yaml", flagFormat)
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&flagFormat, "format", "f", "yaml", "Format (json|yaml)"+"``")
+
+	return cmd
+}
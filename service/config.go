@@ -0,0 +1,274 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/goccy/go-yaml"
+	"github.com/leliuga/cdk/configurator"
+	"github.com/leliuga/cdk/types"
+)
+
+const (
+	// EnvEnvironment is the environment variable which selects the environment and its overlay config file.
+	EnvEnvironment = "ENVIRONMENT"
+)
+
+// Set sets the value at the given dotted json path and records the source of it.
+func (o *Options) Set(path, value string, source Source) error {
+	document, err := toDocument(o)
+	if err != nil {
+		return err
+	}
+
+	current, found := lookupPath(document, path)
+	if !found {
+		return fmt.Errorf("unknown option: %s", path)
+	}
+
+	parsed, err := parseValue(current, value)
+	if err != nil {
+		return fmt.Errorf("invalid value of the option %s: %w", path, err)
+	}
+
+	sparse := map[string]any{}
+	setPath(sparse, path, parsed)
+
+	b, err := json.Marshal(sparse)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(b, o); err != nil {
+		return fmt.Errorf("invalid value of the option %s: %w", path, err)
+	}
+
+	o.record(path, source)
+	if source == SourceFlag {
+		o.flags.Set(path, value)
+	}
+	o.environmentDefaults()
+
+	return nil
+}
+
+// SourceOf returns the source which set the value at the given dotted json path.
+func (o *Options) SourceOf(path string) Source {
+	for p := path; p != ""; {
+		if source, ok := o.Provenance[p]; ok {
+			return source
+		}
+
+		i := strings.LastIndex(p, ".")
+		if i < 0 {
+			break
+		}
+		p = p[:i]
+	}
+
+	return SourceDefault
+}
+
+// Sources returns the source of every value of the options keyed by the dotted json path.
+func (o *Options) Sources() (types.Map[Source], error) {
+	flattened, err := flatten(o)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := types.NewMap[Source]()
+	for path := range flattened {
+		sources.Set(path, o.SourceOf(path))
+	}
+
+	return sources, nil
+}
+
+// loadFile merges the given config file into the options.
+func (o *Options) loadFile(filename string, source Source) error {
+	var document map[string]any
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	switch ext := filepath.Ext(filename); ext {
+	case ".yaml", ".yml":
+		if err = yaml.UnmarshalWithOptions(content, o, yaml.UseJSONUnmarshaler()); err != nil {
+			return err
+		}
+
+		if err = yaml.Unmarshal(content, &document); err != nil {
+			return err
+		}
+	case ".json":
+		if err = json.Unmarshal(content, o); err != nil {
+			return err
+		}
+
+		if err = json.Unmarshal(content, &document); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported config file extension: %s", ext)
+	}
+
+	paths := map[string]any{}
+	flattenInto(paths, "", document)
+
+	for path := range paths {
+		if path != "" {
+			o.record(path, source)
+		}
+	}
+
+	return nil
+}
+
+// loadEnv merges the environment variables into the options.
+func (o *Options) loadEnv() error {
+	return configurator.FromEnvWithTrace(o, "", func(path string) {
+		o.record(path, SourceEnv)
+	})
+}
+
+// overlay returns the environment which selects the overlay config file, the environment variable takes precedence
+// over the config file.
+func (o *Options) overlay() Environment {
+	if environment := ParseEnvironment(os.Getenv(EnvEnvironment)); environment.Validate() {
+		return environment
+	}
+
+	return o.Environment
+}
+
+// configFiles returns the config file and the overlay config file of the environment, the changes of both are reloaded.
+func (o *Options) configFiles() []string {
+	filenames := []string{o.ConfigFile}
+	if overlay := o.overlay(); overlay.Validate() {
+		filenames = append(filenames, overlayFilename(o.ConfigFile, overlay.String()))
+	}
+
+	return filenames
+}
+
+// environmentDefaults sets the defaults which depend on the environment, the values set explicitly are kept.
+func (o *Options) environmentDefaults() {
+	development := o.Environment == EnvironmentDevelopment
+
+	if !o.Provenance.Has("enable_print_routes") {
+		o.EnablePrintRoutes = development
+	}
+
+	if !o.Provenance.Has("verbose_errors") {
+		o.VerboseErrors = development
+	}
+}
+
+// record records the source of the value at the given path, overriding the sources of the values below it.
+func (o *Options) record(path string, source Source) {
+	if o.Provenance == nil {
+		o.Provenance = types.NewMap[Source]()
+	}
+
+	for p := range o.Provenance {
+		if strings.HasPrefix(p, path+".") {
+			o.Provenance.Delete(p)
+		}
+	}
+
+	o.Provenance.Set(path, source)
+}
+
+// overlayFilename returns the filename of the overlay config file, e.g. config.production.yaml.
+func overlayFilename(filename, overlay string) string {
+	ext := filepath.Ext(filename)
+
+	return strings.TrimSuffix(filename, ext) + "." + strings.ToLower(overlay) + ext
+}
+
+// toDocument returns the json representation of the value as a generic document.
+func toDocument(value any) (map[string]any, error) {
+	var document map[string]any
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(b, &document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// lookupPath returns the value at the given dotted path, a missing key of an existing map is found as nil.
+func lookupPath(document map[string]any, path string) (any, bool) {
+	keys := strings.Split(path, ".")
+	current := any(document)
+
+	for i, key := range keys {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		value, exists := m[key]
+		if !exists {
+			return nil, i == len(keys)-1 && i > 0
+		}
+
+		current = value
+	}
+
+	return current, true
+}
+
+// setPath sets the value at the given dotted path, the intermediate maps are created.
+func setPath(document map[string]any, path string, value any) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := document[key].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			document[key] = next
+		}
+		document = next
+	}
+
+	document[keys[len(keys)-1]] = value
+}
+
+// parseValue parses the string value according to the type of the current value.
+func parseValue(current any, value string) (any, error) {
+	switch current.(type) {
+	case string:
+		return value, nil
+	case bool:
+		return strconv.ParseBool(value)
+	case float64:
+		if d, err := time.ParseDuration(value); err == nil {
+			return float64(d), nil
+		}
+
+		return strconv.ParseFloat(value, 64)
+	case []any:
+		if !strings.HasPrefix(strings.TrimSpace(value), "[") {
+			return strings.Split(value, ","), nil
+		}
+	}
+
+	var parsed any
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+		return nil, err
+	}
+
+	return parsed, nil
+}
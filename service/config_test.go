@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionsLayers(t *testing.T) {
+	directory := t.TempDir()
+	filename := filepath.Join(directory, DefaultConfigFile)
+	assert.Nil(t, os.WriteFile(filename, []byte("port: 4000\ndomain: billing.leliuga.com\nruntime:\n  region: eu-west-1\n  zone: eu-west-1a\n"), 0600))
+	assert.Nil(t, os.WriteFile(overlayFilename(filename, "production"), []byte("runtime:\n  zone: eu-west-1b\n"), 0600))
+	t.Setenv("PORT", "5000")
+
+	opts := newOptions()
+	assert.Nil(t, opts.loadFile(filename, SourceFile))
+	assert.Nil(t, opts.loadFile(overlayFilename(filename, "production"), SourceOverlay))
+	assert.Nil(t, opts.loadEnv())
+	assert.Nil(t, opts.Set("read_timeout", "30s", SourceFlag))
+
+	assert.Equal(t, int32(5000), opts.Port)
+	assert.Equal(t, "billing.leliuga.com", opts.Domain)
+	assert.Equal(t, "eu-west-1", opts.Runtime.Region)
+	assert.Equal(t, "eu-west-1b", opts.Runtime.Zone)
+	assert.Equal(t, 30*time.Second, opts.ReadTimeout)
+
+	tests := []struct {
+		tag    string
+		path   string
+		source Source
+	}{
+		{"t0", "name", SourceDefault},
+		{"t1", "port", SourceEnv},
+		{"t2", "domain", SourceFile},
+		{"t3", "runtime.region", SourceFile},
+		{"t4", "runtime.zone", SourceOverlay},
+		{"t5", "runtime.namespace", SourceDefault},
+		{"t6", "read_timeout", SourceFlag},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.source, opts.SourceOf(test.path), test.tag)
+	}
+
+	sources, err := opts.Sources()
+	assert.Nil(t, err)
+	assert.Equal(t, SourceOverlay, sources.Get("runtime.zone"))
+}
+
+func TestOptionsSet(t *testing.T) {
+	opts := newOptions()
+
+	assert.Nil(t, opts.Set("trusted_proxies", "10.0.0.1,10.0.0.2", SourceFlag))
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, opts.TrustedProxies)
+
+	assert.Nil(t, opts.Set("enable_print_routes", "false", SourceFlag))
+	assert.False(t, opts.EnablePrintRoutes)
+
+	assert.Nil(t, opts.Set("runtime.provider", "Amazon Web Service", SourceFlag))
+	assert.Equal(t, ProviderAws, opts.Runtime.Provider)
+
+	assert.NotNil(t, opts.Set("unknown", "value", SourceFlag))
+	assert.NotNil(t, opts.Set("port", "not a number", SourceFlag))
+}
+
+func TestOptionsEnvEnum(t *testing.T) {
+	t.Setenv("CLIENT_AUTH", "require")
+	t.Setenv("RUNTIME_PROVIDER", "Azure")
+
+	opts := NewOptions()
+	assert.Equal(t, ClientAuthRequire, opts.ClientAuth)
+	assert.Equal(t, ProviderAzure, opts.Runtime.Provider)
+	assert.Equal(t, SourceEnv, opts.SourceOf("client_auth"))
+	assert.Equal(t, SourceCode, NewOptions(WithVerboseErrors(false)).SourceOf("verbose_errors"))
+}
+
+func TestOptionsProduction(t *testing.T) {
+	t.Setenv(EnvEnvironment, "")
+
+	opts := NewOptions()
+	assert.Equal(t, EnvironmentProduction, opts.Environment)
+	assert.False(t, opts.VerboseErrors)
+	assert.False(t, opts.EnablePrintRoutes)
+
+	opts.BuildInfo = &BuildInfo{}
+	assert.Nil(t, NewService(opts).Boot(context.Background()))
+
+	opts = NewOptions(WithVerboseErrors(true))
+	assert.NotNil(t, NewService(opts).Boot(context.Background()))
+}
+
+func TestOptionsEnvMalformed(t *testing.T) {
+	t.Setenv("PORT", "not a number")
+
+	assert.NotPanics(t, func() {
+		assert.ErrorContains(t, NewOptions().Validate(), "PORT")
+	})
+
+	t.Setenv("PORT", "")
+	t.Setenv("CLIENT_AUTH", "sometimes")
+	assert.NotPanics(t, func() { _ = NewOptions() })
+}
+
+func TestOptionsEnvironment(t *testing.T) {
+	directory := t.TempDir()
+	filename := filepath.Join(directory, DefaultConfigFile)
+	assert.Nil(t, os.WriteFile(filename, []byte("environment: production\n"), 0600))
+	assert.Nil(t, os.WriteFile(overlayFilename(filename, "production"), []byte("domain: billing.leliuga.com\n"), 0600))
+
+	t.Setenv(EnvEnvironment, "")
+	opts := newOptions()
+	assert.Nil(t, opts.loadFile(filename, SourceFile))
+	assert.Equal(t, EnvironmentProduction, opts.overlay())
+
+	t.Setenv(EnvEnvironment, "staging")
+	assert.Equal(t, EnvironmentStaging, opts.overlay())
+
+	tests := []struct {
+		tag         string
+		options     []Option
+		environment Environment
+		printRoutes bool
+		verbose     bool
+	}{
+		{"t0", nil, EnvironmentStaging, false, false},
+		{"t1", []Option{WithVerboseErrors(true)}, EnvironmentStaging, false, true},
+	}
+
+	for _, test := range tests {
+		opts = NewOptions(test.options...)
+		assert.Equal(t, test.environment, opts.Environment, test.tag)
+		assert.Equal(t, test.printRoutes, opts.EnablePrintRoutes, test.tag)
+		assert.Equal(t, test.verbose, opts.VerboseErrors, test.tag)
+	}
+
+	assert.Nil(t, opts.Set("environment", "development", SourceFlag))
+	assert.True(t, opts.EnablePrintRoutes)
+
+	opts.Environment = EnvironmentProduction
+	assert.NotNil(t, opts.Validate())
+}
+
+func TestOptionsSourceCode(t *testing.T) {
+	options := NewOptions(WithPort(8080), WithTracing(NewTracing()))
+	assert.Equal(t, SourceCode, options.SourceOf("port"))
+	assert.Equal(t, SourceCode, options.SourceOf("tracing.sample_rate"))
+	assert.Equal(t, SourceDefault, options.SourceOf("admin_port"))
+}
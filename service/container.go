@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	ErrDependencyNotFound = errors.New("the dependency is not registered")
+	ErrDependencyCycle    = errors.New("the dependency has a cycle")
+	ErrDependencyType     = errors.New("the dependency has an unexpected type")
+	ErrDependencyScope    = errors.New("the dependency requires a request scope")
+	ErrDependencyKernel   = errors.New("the kernel does not support lazy factories")
+)
+
+type (
+	chainKey        struct{}
+	resolutionKey   struct{}
+	requestScopeKey struct{}
+)
+
+// Provide an instance of type T to the kernel.
+func Provide[T any](k IKernel, name string, instance T) {
+	k.Set(name, instance)
+}
+
+// ProvideFactory defines a lazy factory of type T with the given scope to the kernel, the kernel must implement
+// IContainer.
+func ProvideFactory[T any](k IKernel, name string, scope Scope, factory func(ctx context.Context, k IKernel) (T, error)) error {
+	container, ok := k.(IContainer)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrDependencyKernel, name)
+	}
+
+	return container.Define(name, &Definition{
+		Scope: scope,
+		Factory: func(ctx context.Context, k IKernel) (any, error) {
+			return factory(ctx, k)
+		},
+	})
+}
+
+// Resolve an instance of type T from the kernel, only the instances set to the kernel are resolved when it does not
+// implement IContainer.
+func Resolve[T any](ctx context.Context, k IKernel, name string) (T, error) {
+	var zero T
+
+	instance, err := lookup(ctx, k, name)
+	if err != nil {
+		return zero, err
+	}
+
+	value, ok := instance.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: %s is %T, expected %T", ErrDependencyType, name, instance, zero)
+	}
+
+	return value, nil
+}
+
+// MustResolve an instance of type T from the kernel, it panics on error.
+func MustResolve[T any](ctx context.Context, k IKernel, name string) T {
+	value, err := Resolve[T](ctx, k, name)
+	if err != nil {
+		panic(err)
+	}
+
+	return value
+}
+
+// Define a lazy factory to the kernel.
+func (k *Kernel) Define(name string, definition *Definition) error {
+	k.registry.Lock()
+	defer k.registry.Unlock()
+
+	if !definition.Scope.Validate() {
+		return fmt.Errorf("the dependency %s has an invalid scope", name)
+	}
+
+	if k.instances.Has(name) || k.definitions.Has(name) {
+		return fmt.Errorf("the dependency %s is already registered", name)
+	}
+
+	k.definitions.Set(name, definition)
+
+	return nil
+}
+
+// Lookup an instance from the kernel, the lazy factories are invoked according to their scope.
+func (k *Kernel) Lookup(ctx context.Context, name string) (any, error) {
+	k.registry.RLock()
+	instance, found := k.instances[name]
+	definition := k.definitions[name]
+	k.registry.RUnlock()
+
+	if found {
+		return instance, nil
+	}
+
+	if definition == nil {
+		return nil, fmt.Errorf("%w: %s", ErrDependencyNotFound, name)
+	}
+
+	chain, _ := ctx.Value(chainKey{}).([]string)
+	for _, item := range chain {
+		if item == name {
+			return nil, fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(append(chain, name), " -> "))
+		}
+	}
+
+	ctx = context.WithValue(ctx, chainKey{}, append(chain[:len(chain):len(chain)], name))
+
+	switch definition.Scope {
+	case ScopeSingleton:
+		return k.singleton(ctx, name, definition)
+	case ScopeRequest:
+		scope, ok := ctx.Value(requestScopeKey{}).(*RequestScope)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrDependencyScope, name)
+		}
+
+		return scope.resolve(ctx, k, name, definition)
+	}
+
+	return definition.Factory(ctx, k)
+}
+
+// singleton returns the singleton instance, it is created once while the concurrent lookups wait for it. A lookup
+// which would wait on a lookup waiting for it fails with ErrDependencyCycle instead of deadlocking.
+func (k *Kernel) singleton(ctx context.Context, name string, definition *Definition) (any, error) {
+	parent, _ := ctx.Value(resolutionKey{}).(*resolution)
+	current := &resolution{parent: parent}
+	ctx = context.WithValue(ctx, resolutionKey{}, current)
+
+	k.registry.Lock()
+	for {
+		if instance, found := k.instances[name]; found {
+			k.registry.Unlock()
+			return instance, nil
+		}
+
+		if definition.owner == nil {
+			break
+		}
+
+		if current.blocks(definition) {
+			k.registry.Unlock()
+			return nil, fmt.Errorf("%w: %s is resolved concurrently by a lookup waiting for it", ErrDependencyCycle, name)
+		}
+
+		done := definition.done
+		current.wait(definition)
+		k.registry.Unlock()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			k.registry.Lock()
+			current.wait(nil)
+			k.registry.Unlock()
+			return nil, ctx.Err()
+		}
+
+		k.registry.Lock()
+		current.wait(nil)
+	}
+
+	definition.owner = current
+	definition.done = make(chan struct{})
+	k.registry.Unlock()
+
+	instance, err := definition.Factory(ctx, k)
+
+	k.registry.Lock()
+	defer k.registry.Unlock()
+
+	if err == nil {
+		k.instances.Set(name, instance)
+	}
+	definition.owner = nil
+	close(definition.done)
+
+	return instance, err
+}
+
+// blocks returns true if the owner of the definition waits, directly or through other lookups, for the resolution or
+// one of its parents.
+func (r *resolution) blocks(definition *Definition) bool {
+	for owner, seen := definition.owner, map[*resolution]bool{}; owner != nil && !seen[owner]; {
+		for current := r; current != nil; current = current.parent {
+			if current == owner {
+				return true
+			}
+		}
+
+		seen[owner] = true
+		if owner.waiting == nil {
+			return false
+		}
+		owner = owner.waiting.owner
+	}
+
+	return false
+}
+
+// wait records the definition the resolution and its parents wait for.
+func (r *resolution) wait(definition *Definition) {
+	for current := r; current != nil; current = current.parent {
+		current.waiting = definition
+	}
+}
+
+// lookup an instance from the kernel, it falls back to the instances set to the kernel when it does not implement
+// IContainer.
+func lookup(ctx context.Context, k IKernel, name string) (any, error) {
+	if container, ok := k.(IContainer); ok {
+		return container.Lookup(ctx, name)
+	}
+
+	if !k.Has(name) {
+		return nil, fmt.Errorf("%w: %s", ErrDependencyNotFound, name)
+	}
+
+	return k.Get(name), nil
+}
+
+// NewRequestScope returns a context carrying a new request scope.
+func NewRequestScope(ctx context.Context) (context.Context, *RequestScope) {
+	scope := &RequestScope{
+		instances: map[string]any{},
+		mutex:     &sync.Mutex{},
+	}
+
+	return context.WithValue(ctx, requestScopeKey{}, scope), scope
+}
+
+// Close closes the request scoped instances which implement io.Closer.
+func (r *RequestScope) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var errs []error
+	for _, instance := range r.instances {
+		if closer, ok := instance.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	r.instances = map[string]any{}
+
+	return errors.Join(errs...)
+}
+
+// RequestScopeHandler returns a fiber handler which opens a request scope for every request.
+func RequestScopeHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, scope := NewRequestScope(c.UserContext())
+		c.SetUserContext(ctx)
+
+		defer scope.Close()
+
+		return c.Next()
+	}
+}
+
+// resolve returns the request scoped instance, it is created on first use.
+func (r *RequestScope) resolve(ctx context.Context, k *Kernel, name string, definition *Definition) (any, error) {
+	r.mutex.Lock()
+	instance, found := r.instances[name]
+	r.mutex.Unlock()
+
+	if found {
+		return instance, nil
+	}
+
+	instance, err := definition.Factory(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if existing, found := r.instances[name]; found {
+		return existing, nil
+	}
+
+	r.instances[name] = instance
+
+	return instance, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type counter struct {
+	value  int
+	closed bool
+}
+
+func (c *counter) Close() error {
+	c.closed = true
+
+	return nil
+}
+
+type plainKernel struct {
+	IKernel
+}
+
+func TestResolve(t *testing.T) {
+	ctx := context.Background()
+	k := NewKernel()
+
+	Provide(k, "name", "billing")
+
+	name, err := Resolve[string](ctx, k, "name")
+	assert.Nil(t, err)
+	assert.Equal(t, "billing", name)
+
+	_, err = Resolve[int](ctx, k, "name")
+	assert.ErrorIs(t, err, ErrDependencyType)
+
+	_, err = Resolve[string](ctx, k, "missing")
+	assert.ErrorIs(t, err, ErrDependencyNotFound)
+
+	assert.Panics(t, func() { MustResolve[string](ctx, k, "missing") })
+}
+
+func TestResolveScopes(t *testing.T) {
+	ctx := context.Background()
+	k := NewKernel()
+	created := 0
+	factory := func(context.Context, IKernel) (*counter, error) {
+		created++
+		return &counter{value: created}, nil
+	}
+
+	assert.Nil(t, ProvideFactory(k, "singleton", ScopeSingleton, factory))
+	assert.Nil(t, ProvideFactory(k, "transient", ScopeTransient, factory))
+	assert.Nil(t, ProvideFactory(k, "request", ScopeRequest, factory))
+	assert.NotNil(t, ProvideFactory(k, "singleton", ScopeSingleton, factory))
+
+	a := MustResolve[*counter](ctx, k, "singleton")
+	b := MustResolve[*counter](ctx, k, "singleton")
+	assert.Same(t, a, b)
+
+	a = MustResolve[*counter](ctx, k, "transient")
+	b = MustResolve[*counter](ctx, k, "transient")
+	assert.NotSame(t, a, b)
+
+	_, err := Resolve[*counter](ctx, k, "request")
+	assert.ErrorIs(t, err, ErrDependencyScope)
+
+	requestCtx, scope := NewRequestScope(ctx)
+	a = MustResolve[*counter](requestCtx, k, "request")
+	b = MustResolve[*counter](requestCtx, k, "request")
+	assert.Same(t, a, b)
+
+	otherCtx, _ := NewRequestScope(ctx)
+	assert.NotSame(t, a, MustResolve[*counter](otherCtx, k, "request"))
+
+	assert.Nil(t, scope.Close())
+	assert.True(t, a.closed)
+}
+
+func TestResolveCycle(t *testing.T) {
+	k := NewKernel()
+
+	assert.Nil(t, ProvideFactory(k, "a", ScopeSingleton, func(ctx context.Context, k IKernel) (string, error) {
+		return Resolve[string](ctx, k, "b")
+	}))
+	assert.Nil(t, ProvideFactory(k, "b", ScopeSingleton, func(ctx context.Context, k IKernel) (string, error) {
+		return Resolve[string](ctx, k, "a")
+	}))
+
+	_, err := Resolve[string](context.Background(), k, "a")
+	assert.ErrorIs(t, err, ErrDependencyCycle)
+	assert.Contains(t, err.Error(), "a -> b -> a")
+}
+
+func TestResolveConcurrentCycle(t *testing.T) {
+	k := NewKernel()
+	started := &atomic.Int32{}
+	both := make(chan struct{})
+
+	factory := func(dependency string) func(ctx context.Context, k IKernel) (string, error) {
+		return func(ctx context.Context, k IKernel) (string, error) {
+			if started.Add(1) == 2 {
+				close(both)
+			}
+			<-both
+
+			return Resolve[string](ctx, k, dependency)
+		}
+	}
+
+	assert.Nil(t, ProvideFactory(k, "a", ScopeSingleton, factory("b")))
+	assert.Nil(t, ProvideFactory(k, "b", ScopeSingleton, factory("a")))
+
+	errs := make(chan error, 2)
+	for _, name := range []string{"a", "b"} {
+		go func(name string) {
+			_, err := Resolve[string](context.Background(), k, name)
+			errs <- err
+		}(name)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			assert.ErrorIs(t, err, ErrDependencyCycle)
+		case <-time.After(time.Second):
+			t.Fatal("the concurrent lookups are deadlocked")
+		}
+	}
+}
+
+func TestResolveWithoutContainer(t *testing.T) {
+	kernel := &plainKernel{NewKernel()}
+
+	Provide(kernel, "name", "billing")
+	assert.Equal(t, "billing", MustResolve[string](context.Background(), kernel, "name"))
+
+	_, err := Resolve[string](context.Background(), kernel, "missing")
+	assert.ErrorIs(t, err, ErrDependencyNotFound)
+	assert.ErrorIs(t, ProvideFactory(kernel, "lazy", ScopeSingleton, func(context.Context, IKernel) (string, error) { return "", nil }), ErrDependencyKernel)
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Default values for the runtime detector
+const (
+	DefaultNamespaceFile   = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+	DefaultCgroupDirectory = "/sys/fs/cgroup"
+	DefaultDetectTimeout   = 500 * time.Millisecond
+	DefaultDetectRuntime   = false
+)
+
+const (
+	// EnvPodNamespace is the downward API environment variable which holds the namespace of the pod.
+	EnvPodNamespace = "POD_NAMESPACE"
+
+	// EnvNodeRegion is the environment variable which holds the LabelRegion label of the node, the downward API does
+	// not expose the node labels so it is set by the deployment.
+	EnvNodeRegion = "NODE_REGION"
+
+	// EnvNodeZone is the environment variable which holds the LabelZone label of the node, the downward API does not
+	// expose the node labels so it is set by the deployment.
+	EnvNodeZone = "NODE_ZONE"
+
+	// LabelRegion is the well known label of the region of a node.
+	LabelRegion = "topology.kubernetes.io/region"
+
+	// LabelZone is the well known label of the zone of a node.
+	LabelZone = "topology.kubernetes.io/zone"
+)
+
+// cgroupUnlimited is the threshold above which a cgroup v1 limit means no limit.
+const cgroupUnlimited = 1 << 62
+
+// NewRuntimeDetector returns a new runtime detector which probes the metadata endpoints of the known providers.
+func NewRuntimeDetector() *RuntimeDetector {
+	return &RuntimeDetector{
+		NamespaceFile:   DefaultNamespaceFile,
+		CgroupDirectory: DefaultCgroupDirectory,
+		Timeout:         DefaultDetectTimeout,
+		Probes: []IMetadataProbe{
+			NewAwsMetadataProbe(""),
+			NewGcpMetadataProbe(""),
+			NewAzureMetadataProbe(""),
+			NewDoMetadataProbe(""),
+		},
+	}
+}
+
+// Detect returns the runtime values detected from the execution environment. The region and zone are read from the
+// NODE_REGION and NODE_ZONE environment variables, falling back to the metadata endpoint of the provider.
+func (d *RuntimeDetector) Detect(ctx context.Context) *RuntimeDetection {
+	detection := &RuntimeDetection{
+		Namespace: d.namespace(),
+		Region:    os.Getenv(EnvNodeRegion),
+		Zone:      os.Getenv(EnvNodeZone),
+		Limits:    d.limits(),
+	}
+
+	if provider, region, zone, ok := d.probe(ctx); ok {
+		detection.Provider = provider
+		detection.Region = firstNonEmpty(detection.Region, region)
+		detection.Zone = firstNonEmpty(detection.Zone, zone)
+	}
+
+	return detection
+}
+
+// Apply sets the detected values to the runtime of the options, the values set explicitly are kept.
+func (r *RuntimeDetection) Apply(o *Options) {
+	defaults := NewRuntime()
+	detected := func(path string, current, fallback any) bool {
+		return o.SourceOf(path) == SourceDefault && current == fallback
+	}
+
+	if r.Provider.Validate() && detected("runtime.provider", o.Runtime.Provider, defaults.Provider) {
+		o.Runtime.Provider = r.Provider
+		o.record("runtime.provider", SourceDetected)
+	}
+
+	if r.Region != "" && detected("runtime.region", o.Runtime.Region, defaults.Region) {
+		o.Runtime.Region = r.Region
+		o.record("runtime.region", SourceDetected)
+	}
+
+	if r.Zone != "" && detected("runtime.zone", o.Runtime.Zone, defaults.Zone) {
+		o.Runtime.Zone = r.Zone
+		o.record("runtime.zone", SourceDetected)
+	}
+
+	if r.Namespace != "" && detected("runtime.namespace", o.Runtime.Namespace, defaults.Namespace) {
+		o.Runtime.Namespace = r.Namespace
+		o.record("runtime.namespace", SourceDetected)
+	}
+
+	if o.Runtime.Resources == nil {
+		return
+	}
+
+	for name, quantity := range r.Limits {
+		path := "runtime.resources.limits." + string(name)
+		current, found := o.Runtime.Resources.Limits[name]
+		if o.SourceOf(path) != SourceDefault || (found && !current.Equal(defaults.Resources.Limits[name])) {
+			continue
+		}
+
+		if o.Runtime.Resources.Limits == nil {
+			o.Runtime.Resources.Limits = corev1.ResourceList{}
+		}
+		o.Runtime.Resources.Limits[name] = quantity
+		o.record(path, SourceDetected)
+	}
+}
+
+// namespace returns the namespace from the downward API or the service account, empty outside of Kubernetes.
+func (d *RuntimeDetector) namespace() string {
+	if namespace := os.Getenv(EnvPodNamespace); namespace != "" {
+		return namespace
+	}
+
+	content, err := os.ReadFile(d.NamespaceFile)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(content))
+}
+
+// limits returns the cpu and memory limits from the cgroup v2 or v1 hierarchy.
+func (d *RuntimeDetector) limits() corev1.ResourceList {
+	limits := corev1.ResourceList{}
+
+	if fields := strings.Fields(readCgroup(d.CgroupDirectory, "cpu.max")); len(fields) == 2 {
+		setCPULimit(limits, fields[0], fields[1])
+	} else {
+		setCPULimit(limits, readCgroup(d.CgroupDirectory, "cpu", "cpu.cfs_quota_us"), readCgroup(d.CgroupDirectory, "cpu", "cpu.cfs_period_us"))
+	}
+
+	memory := readCgroup(d.CgroupDirectory, "memory.max")
+	if memory == "" {
+		memory = readCgroup(d.CgroupDirectory, "memory", "memory.limit_in_bytes")
+	}
+
+	if bytes, err := strconv.ParseInt(memory, 10, 64); err == nil && bytes > 0 && bytes < cgroupUnlimited {
+		limits[corev1.ResourceMemory] = *resource.NewQuantity(bytes, resource.BinarySI)
+	}
+
+	if len(limits) == 0 {
+		return nil
+	}
+
+	return limits
+}
+
+// probe runs the metadata probes concurrently and returns the placement of the first one which succeeds.
+func (d *RuntimeDetector) probe(ctx context.Context) (Provider, string, string, bool) {
+	if len(d.Probes) == 0 {
+		return ProviderInvalid, "", "", false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout)
+	defer cancel()
+
+	type result struct {
+		provider Provider
+		region   string
+		zone     string
+		err      error
+	}
+
+	client := &http.Client{Timeout: d.Timeout}
+	results := make(chan result, len(d.Probes))
+
+	for _, p := range d.Probes {
+		go func(p IMetadataProbe) {
+			region, zone, err := p.Probe(ctx, client)
+			results <- result{provider: p.Provider(), region: region, zone: zone, err: err}
+		}(p)
+	}
+
+	for range d.Probes {
+		if r := <-results; r.err == nil {
+			return r.provider, r.region, r.zone, true
+		}
+	}
+
+	return ProviderInvalid, "", "", false
+}
+
+// setCPULimit sets the cpu limit from the cgroup quota and period, a missing or unlimited quota is ignored.
+func setCPULimit(limits corev1.ResourceList, quota, period string) {
+	q, err := strconv.ParseInt(quota, 10, 64)
+	if err != nil || q <= 0 {
+		return
+	}
+
+	p, err := strconv.ParseInt(period, 10, 64)
+	if err != nil || p <= 0 {
+		return
+	}
+
+	limits[corev1.ResourceCPU] = *resource.NewMilliQuantity(q*1000/p, resource.DecimalSI)
+}
+
+// readCgroup returns the trimmed content of the cgroup file, empty when it does not exist.
+func readCgroup(elements ...string) string {
+	content, err := os.ReadFile(filepath.Join(elements...))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(content))
+}
+
+// firstNonEmpty returns the first value which is not empty.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
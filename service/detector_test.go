@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataProbes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest/api/token":
+			if r.Method != http.MethodPut {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			_, _ = w.Write([]byte("token"))
+		case "/latest/meta-data/placement/region":
+			if r.Header.Get("X-aws-ec2-metadata-token") != "token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte("eu-west-1"))
+		case "/latest/meta-data/placement/availability-zone":
+			_, _ = w.Write([]byte("eu-west-1a"))
+		case "/computeMetadata/v1/instance/zone":
+			if r.Header.Get("Metadata-Flavor") != "Google" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte("projects/123/zones/europe-west4-b"))
+		case "/metadata/instance/compute":
+			if r.Header.Get("Metadata") != "true" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"vmId":"02aab8a4-74ef-476e-8182-f6d2ba4166a6","location":"westeurope","zone":"2"}`))
+		case "/empty/metadata/instance/compute":
+			_, _ = w.Write([]byte(`{}`))
+		case "/metadata/v1/region":
+			_, _ = w.Write([]byte("ams3"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		tag      string
+		probe    IMetadataProbe
+		provider Provider
+		region   string
+		zone     string
+	}{
+		{"t0", NewAwsMetadataProbe(server.URL), ProviderAws, "eu-west-1", "eu-west-1a"},
+		{"t1", NewGcpMetadataProbe(server.URL), ProviderGcp, "europe-west4", "europe-west4-b"},
+		{"t2", NewAzureMetadataProbe(server.URL), ProviderAzure, "westeurope", "2"},
+		{"t3", NewDoMetadataProbe(server.URL), ProviderDo, "ams3", ""},
+	}
+
+	for _, test := range tests {
+		region, zone, err := test.probe.Probe(context.Background(), server.Client())
+		assert.Nil(t, err, test.tag)
+		assert.Equal(t, test.provider, test.probe.Provider(), test.tag)
+		assert.Equal(t, test.region, region, test.tag)
+		assert.Equal(t, test.zone, zone, test.tag)
+	}
+
+	_, _, err := NewAwsMetadataProbe(server.URL+"/missing").Probe(context.Background(), server.Client())
+	assert.ErrorIs(t, err, ErrMetadataUnavailable)
+
+	_, _, err = NewAzureMetadataProbe(server.URL+"/empty").Probe(context.Background(), server.Client())
+	assert.ErrorIs(t, err, ErrMetadataUnavailable)
+}
+
+func TestRuntimeDetector(t *testing.T) {
+	directory := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(directory, "namespace"), []byte("billing\n"), 0600))
+	t.Setenv(EnvNodeZone, "ams3-b")
+	assert.Nil(t, os.WriteFile(filepath.Join(directory, "cpu.max"), []byte("150000 100000\n"), 0600))
+	assert.Nil(t, os.WriteFile(filepath.Join(directory, "memory.max"), []byte("536870912\n"), 0600))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ams3"))
+	}))
+	defer server.Close()
+
+	detector := &RuntimeDetector{
+		NamespaceFile:   filepath.Join(directory, "namespace"),
+		CgroupDirectory: directory,
+		Timeout:         time.Second,
+		Probes: []IMetadataProbe{
+			NewAwsMetadataProbe(server.URL + "/missing"),
+			NewDoMetadataProbe(server.URL),
+		},
+	}
+
+	detection := detector.Detect(context.Background())
+	assert.Equal(t, ProviderDo, detection.Provider)
+	assert.Equal(t, "ams3", detection.Region)
+	assert.Equal(t, "ams3-b", detection.Zone)
+	assert.Equal(t, "billing", detection.Namespace)
+	assert.Equal(t, int64(1500), detection.Limits.Cpu().MilliValue())
+	assert.Equal(t, int64(512<<20), detection.Limits.Memory().Value())
+
+	t.Setenv("RUNTIME_REGION", "eu-central-1")
+	opts := NewOptions()
+	detection.Apply(opts)
+
+	tests := []struct {
+		tag    string
+		path   string
+		source Source
+	}{
+		{"t0", "runtime.provider", SourceDetected},
+		{"t1", "runtime.region", SourceEnv},
+		{"t2", "runtime.zone", SourceDetected},
+		{"t3", "runtime.namespace", SourceDetected},
+		{"t4", "runtime.resources.limits.cpu", SourceDetected},
+		{"t5", "runtime.resources.limits.ephemeral-storage", SourceDefault},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.source, opts.SourceOf(test.path), test.tag)
+	}
+
+	assert.Equal(t, "eu-central-1", opts.Runtime.Region)
+	assert.Equal(t, "billing", opts.Runtime.Namespace)
+	assert.Equal(t, "1500m", opts.Runtime.Resources.Limits.Cpu().String())
+}
+
+func TestRuntimeDetectorCgroupV1(t *testing.T) {
+	directory := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(directory, "cpu"), 0700))
+	assert.Nil(t, os.MkdirAll(filepath.Join(directory, "memory"), 0700))
+	assert.Nil(t, os.WriteFile(filepath.Join(directory, "cpu", "cpu.cfs_quota_us"), []byte("-1\n"), 0600))
+	assert.Nil(t, os.WriteFile(filepath.Join(directory, "cpu", "cpu.cfs_period_us"), []byte("100000\n"), 0600))
+	assert.Nil(t, os.WriteFile(filepath.Join(directory, "memory", "memory.limit_in_bytes"), []byte("268435456\n"), 0600))
+
+	detector := &RuntimeDetector{CgroupDirectory: directory}
+	limits := detector.limits()
+	assert.False(t, limits.Cpu().Value() > 0)
+	assert.Equal(t, int64(256<<20), limits.Memory().Value())
+}
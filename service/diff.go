@@ -0,0 +1,91 @@
+package service
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// NewConfigDiff returns the changes between the previous and the next options.
+func NewConfigDiff(previous, next *Options) (ConfigDiff, error) {
+	before, err := flatten(previous)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := flatten(next)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := ConfigDiff{}
+	for path, value := range before {
+		if other, ok := after[path]; !ok || !reflect.DeepEqual(value, other) {
+			diff = append(diff, &ConfigChange{Path: path, Previous: value, Next: after[path]})
+		}
+	}
+
+	for path, value := range after {
+		if _, ok := before[path]; !ok {
+			diff = append(diff, &ConfigChange{Path: path, Next: value})
+		}
+	}
+
+	sort.Slice(diff, func(i, j int) bool {
+		return diff[i].Path < diff[j].Path
+	})
+
+	return diff, nil
+}
+
+// Has returns true if the value at the given path, or any value below it, has changed.
+func (d ConfigDiff) Has(path string) bool {
+	for _, change := range d {
+		if change.Path == path || strings.HasPrefix(change.Path, path+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Paths returns the changed paths.
+func (d ConfigDiff) Paths() []string {
+	paths := make([]string, 0, len(d))
+	for _, change := range d {
+		paths = append(paths, change.Path)
+	}
+
+	return paths
+}
+
+// flatten returns the json representation of the value as a map of dotted paths.
+func flatten(value any) (map[string]any, error) {
+	document, err := toDocument(value)
+	if err != nil {
+		return nil, err
+	}
+
+	flattened := map[string]any{}
+	flattenInto(flattened, "", document)
+
+	return flattened, nil
+}
+
+// flattenInto walks the nested maps and stores the leaves under their dotted paths.
+func flattenInto(flattened map[string]any, path string, value any) {
+	if m, ok := value.(map[string]any); ok && len(m) > 0 {
+		for key, item := range m {
+			p := key
+			if path != "" {
+				p = path + "." + key
+			}
+
+			flattenInto(flattened, p, item)
+		}
+
+		return
+	}
+
+	flattened[path] = value
+}
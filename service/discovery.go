@@ -0,0 +1,163 @@
+package service
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// Redacted is the placeholder for the redacted values.
+	Redacted = "[REDACTED]"
+)
+
+var (
+	SensitiveKeyRegex = regexp.MustCompile(`(?i)(secret|password|passwd|token|credential|certificate|private|dsn|key$)`)
+
+	// RedactedPaths are the paths redacted by the default redaction policy, the compiler and linker flags of the build
+	// settings may embed secrets with -X or the paths of the build host.
+	RedactedPaths = []string{
+		"build_info.settings.-asmflags",
+		"build_info.settings.-gcflags",
+		"build_info.settings.-ldflags",
+		"build_info.settings.CGO_CFLAGS",
+		"build_info.settings.CGO_CPPFLAGS",
+		"build_info.settings.CGO_CXXFLAGS",
+		"build_info.settings.CGO_LDFLAGS",
+	}
+)
+
+// NewDiscovery creates a new Discovery of the given service.
+func NewDiscovery(s *Service) *Discovery {
+	options := s.Current()
+	d := &Discovery{
+		Name:        options.Name,
+		Description: options.Description,
+		Domain:      options.Domain,
+		Environment: options.Environment,
+		BuildInfo:   options.BuildInfo,
+		Routes:      []*DiscoveryRoute{},
+	}
+
+	if r := options.Runtime; r != nil {
+		d.Runtime = &DiscoveryRuntime{
+			Provider:  r.Provider,
+			Region:    r.Region,
+			Zone:      r.Zone,
+			Namespace: r.Namespace,
+			Engine:    r.Engine,
+		}
+	}
+
+	routes := map[string]*DiscoveryRoute{}
+	for _, route := range s.GetRoutes(true) {
+		r, ok := routes[route.Path]
+		if !ok {
+			r = &DiscoveryRoute{Path: route.Path, Methods: []string{}}
+			routes[route.Path] = r
+			d.Routes = append(d.Routes, r)
+		}
+
+		if r.Name == "" {
+			r.Name = route.Name
+		}
+
+		r.Methods = append(r.Methods, route.Method)
+	}
+
+	sort.Slice(d.Routes, func(i, j int) bool {
+		return d.Routes[i].Path < d.Routes[j].Path
+	})
+
+	for _, r := range d.Routes {
+		sort.Strings(r.Methods)
+	}
+
+	return d
+}
+
+// Redact returns the Discovery as a generic document with the policy applied to every value.
+func (d *Discovery) Redact(policy RedactionPolicy) (map[string]any, error) {
+	document, err := toDocument(d)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy == nil {
+		return document, nil
+	}
+
+	return redact("", document, policy).(map[string]any), nil
+}
+
+// DiscoveryHandler returns a fiber handler which serves the service discovery.
+func (s *Service) DiscoveryHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		document, err := NewDiscovery(s).Redact(s.Options.Redaction)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(document)
+	}
+}
+
+// DefaultRedactionPolicy redacts the values at RedactedPaths, the values of sensitive keys and the credentials of URLs.
+func DefaultRedactionPolicy(path string, value any) any {
+	for _, redacted := range RedactedPaths {
+		if path == redacted {
+			return Redacted
+		}
+	}
+
+	key := path
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		key = path[i+1:]
+	}
+
+	if SensitiveKeyRegex.MatchString(key) {
+		return Redacted
+	}
+
+	if str, ok := value.(string); ok && strings.Contains(str, "@") {
+		if u, err := url.Parse(str); err == nil && u.User != nil {
+			u.User = nil
+			return u.String()
+		}
+	}
+
+	return value
+}
+
+// redact walks the given value and applies the policy to every leaf.
+func redact(path string, value any, policy RedactionPolicy) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			p := key
+			if path != "" {
+				p = path + "." + key
+			}
+
+			if redacted := policy(p, item); redacted == Redacted {
+				v[key] = redacted
+				continue
+			}
+
+			v[key] = redact(p, item, policy)
+		}
+
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = redact(path, item, policy)
+		}
+
+		return v
+	}
+
+	return policy(path, value)
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/types"
+)
+
+const (
+	// HealthCheckService is the name of the built-in check reflecting the service state.
+	HealthCheckService = "service"
+)
+
+// NewHealth creates a new Health with the given timeout per check.
+func NewHealth(timeout time.Duration) *Health {
+	return &Health{
+		timeout: timeout,
+		checks: map[Probe]types.Map[HealthCheck]{
+			ProbeLiveness:  types.NewMap[HealthCheck](),
+			ProbeReadiness: types.NewMap[HealthCheck](),
+			ProbeStartup:   types.NewMap[HealthCheck](),
+		},
+		mutex:   &sync.RWMutex{},
+		ready:   &atomic.Bool{},
+		started: &atomic.Bool{},
+	}
+}
+
+// Register registers a named check for the given probes, all probes are used when none is given.
+func (h *Health) Register(name string, check HealthCheck, probes ...Probe) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if len(probes) == 0 {
+		probes = []Probe{ProbeLiveness, ProbeReadiness, ProbeStartup}
+	}
+
+	for _, probe := range probes {
+		if checks, ok := h.checks[probe]; ok {
+			checks.Set(name, check)
+		}
+	}
+}
+
+// Unregister removes a named check from all probes.
+func (h *Health) Unregister(name string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for _, checks := range h.checks {
+		checks.Delete(name)
+	}
+}
+
+// SetReady marks the service as ready or not ready to receive traffic.
+func (h *Health) SetReady(value bool) {
+	h.ready.Store(value)
+}
+
+// Ready returns true if the service is ready to receive traffic.
+func (h *Health) Ready() bool {
+	return h.ready.Load()
+}
+
+// SetStarted marks the service as started or not started.
+func (h *Health) SetStarted(value bool) {
+	h.started.Store(value)
+}
+
+// Started returns true if the service is started.
+func (h *Health) Started() bool {
+	return h.started.Load()
+}
+
+// Check runs all checks registered for the given probe and returns the aggregated report.
+func (h *Health) Check(ctx context.Context, probe Probe) *HealthReport {
+	h.mutex.RLock()
+	checks := h.checks[probe].Clone()
+	h.mutex.RUnlock()
+
+	report := &HealthReport{
+		Probe:  probe,
+		Status: HealthStatusUp,
+		Checks: types.NewMap[*HealthCheckResult](),
+	}
+
+	switch {
+	case probe == ProbeReadiness && !h.Ready():
+		report.Checks.Set(HealthCheckService, &HealthCheckResult{Status: HealthStatusDown, Error: "the service is not ready"})
+	case probe == ProbeStartup && !h.Started():
+		report.Checks.Set(HealthCheckService, &HealthCheckResult{Status: HealthStatusDown, Error: "the service is not started"})
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+	)
+
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check HealthCheck) {
+			defer wg.Done()
+
+			result := h.run(ctx, check)
+
+			mutex.Lock()
+			report.Checks.Set(name, result)
+			mutex.Unlock()
+		}(name, check)
+	}
+
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != HealthStatusUp {
+			report.Status = HealthStatusDown
+			break
+		}
+	}
+
+	return report
+}
+
+// Handler returns a fiber handler which serves the report of the given probe.
+func (h *Health) Handler(probe Probe) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		report := h.Check(c.UserContext(), probe)
+
+		status := fiber.StatusOK
+		if report.Status != HealthStatusUp {
+			status = fiber.StatusServiceUnavailable
+		}
+
+		c.Set(fiber.HeaderCacheControl, "no-store")
+
+		return c.Status(status).JSON(report)
+	}
+}
+
+// run runs a single check within the health timeout, the check runs in its own goroutine so a check ignoring the
+// context does not hold the report past the timeout.
+func (h *Health) run(ctx context.Context, check HealthCheck) *HealthCheckResult {
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	done := make(chan *HealthCheckResult, 1)
+
+	go func() {
+		result := &HealthCheckResult{
+			Status: HealthStatusUp,
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				result.Status = HealthStatusDown
+				result.Error = "the check panicked"
+			}
+			done <- result
+		}()
+
+		if err := check(ctx); err != nil {
+			result.Status = HealthStatusDown
+			result.Error = err.Error()
+		}
+	}()
+
+	var result *HealthCheckResult
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result = &HealthCheckResult{Status: HealthStatusDown, Error: ctx.Err().Error()}
+	}
+	result.Latency = time.Since(start).String()
+
+	return result
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+)
+
+const (
+	HealthStatusInvalid HealthStatus = iota //
+	HealthStatusUp
+	HealthStatusDown
+)
+
+var (
+	HealthStatusNames = map[HealthStatus]string{
+		HealthStatusUp:   "up",
+		HealthStatusDown: "down",
+	}
+)
+
+// String outputs the HealthStatus as a string.
+func (h HealthStatus) String() string {
+	return HealthStatusNames[h]
+}
+
+// MarshalJSON outputs the HealthStatus as a json.
+func (h HealthStatus) MarshalJSON() ([]byte, error) {
+	if !h.Validate() {
+		return []byte(`""`), nil
+	}
+
+	return []byte(`"` + h.String() + `"`), nil
+}
+
+// UnmarshalJSON parses the HealthStatus from json.
+func (h *HealthStatus) UnmarshalJSON(data []byte) error {
+	str := string(bytes.Trim(data, `"`))
+	if status := ParseHealthStatus(str); status.Validate() {
+		*h = status
+	}
+
+	return nil
+}
+
+// Validate returns true if the HealthStatus is valid.
+func (h HealthStatus) Validate() bool {
+	return h != HealthStatusInvalid
+}
+
+// ParseHealthStatus parses the HealthStatus from string.
+func ParseHealthStatus(value string) HealthStatus {
+	value = strings.ToLower(value)
+	for k, v := range HealthStatusNames {
+		if v == value {
+			return k
+		}
+	}
+
+	return HealthStatusInvalid
+}
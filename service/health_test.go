@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthCheck(t *testing.T) {
+	failing := func(context.Context) error { return errors.New("connection refused") }
+	passing := func(context.Context) error { return nil }
+
+	tests := []struct {
+		tag     string
+		probe   Probe
+		ready   bool
+		started bool
+		checks  map[string]HealthCheck
+		status  HealthStatus
+	}{
+		{"t0", ProbeLiveness, false, false, nil, HealthStatusUp},
+		{"t1", ProbeLiveness, true, true, map[string]HealthCheck{"db": passing}, HealthStatusUp},
+		{"t2", ProbeLiveness, true, true, map[string]HealthCheck{"db": passing, "cache": failing}, HealthStatusDown},
+		{"t3", ProbeReadiness, false, true, nil, HealthStatusDown},
+		{"t4", ProbeReadiness, true, true, map[string]HealthCheck{"db": passing}, HealthStatusUp},
+		{"t5", ProbeStartup, true, false, nil, HealthStatusDown},
+		{"t6", ProbeStartup, true, true, nil, HealthStatusUp},
+	}
+
+	for _, test := range tests {
+		h := NewHealth(0)
+		h.SetReady(test.ready)
+		h.SetStarted(test.started)
+		for name, check := range test.checks {
+			h.Register(name, check, test.probe)
+		}
+
+		report := h.Check(context.Background(), test.probe)
+		assert.Equal(t, test.status, report.Status, test.tag)
+		for name := range test.checks {
+			assert.True(t, report.Checks.Has(name), test.tag)
+			assert.NotEmpty(t, report.Checks.Get(name).Latency, test.tag)
+		}
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	h := NewHealth(0)
+	h.SetStarted(true)
+
+	app := fiber.New()
+	app.Get(DefaultPathMonitoringReadiness, h.Handler(ProbeReadiness))
+
+	res, err := app.Test(httptest.NewRequest(fiber.MethodGet, DefaultPathMonitoringReadiness, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusServiceUnavailable, res.StatusCode)
+	assert.Equal(t, fiber.MIMEApplicationJSON, res.Header.Get(fiber.HeaderContentType))
+
+	h.SetReady(true)
+	res, err = app.Test(httptest.NewRequest(fiber.MethodGet, DefaultPathMonitoringReadiness, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusOK, res.StatusCode)
+}
+
+func TestHealthCheckTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	h := NewHealth(10 * time.Millisecond)
+	h.Register("blocking", func(context.Context) error { <-release; return nil }, ProbeLiveness)
+
+	start := time.Now()
+	report := h.Check(context.Background(), ProbeLiveness)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, HealthStatusDown, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks.Get("blocking").Error)
+}
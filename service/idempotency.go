@@ -0,0 +1,82 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/storage/memory"
+	"github.com/leliuga/cdk/service/middleware"
+	"github.com/leliuga/cdk/service/middleware/idempotency"
+	"github.com/leliuga/cdk/types"
+	"github.com/leliuga/cdk/validation"
+)
+
+// Default values for the Service idempotency
+const (
+	DefaultIdempotencyEnabled   = false
+	DefaultIdempotencyRetention = 24 * time.Hour
+)
+
+// NewIdempotency creates a new Idempotency.
+func NewIdempotency() *Idempotency {
+	return &Idempotency{
+		Enabled:   DefaultIdempotencyEnabled,
+		Retention: DefaultIdempotencyRetention,
+		Exclude:   []string{DefaultPathMonitoring},
+		Routes:    types.NewMap[time.Duration](),
+	}
+}
+
+// Handler returns the idempotency middleware, it passes the requests through when the idempotency is disabled. The
+// retention of the route with the longest path prefix of the request applies, see middleware.HasPathPrefix, the others
+// use the default retention.
+func (i *Idempotency) Handler() fiber.Handler {
+	if i == nil || !i.Enabled {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	if i.Storage == nil {
+		i.Storage = memory.New()
+	}
+
+	routes := i.Routes.Keys()
+
+	// The longest prefixes are matched first.
+	sort.Slice(routes, func(a, b int) bool {
+		return len(routes[a]) > len(routes[b])
+	})
+
+	return idempotency.New(idempotency.Config{
+		Next: func(c *fiber.Ctx) bool {
+			for _, prefix := range i.Exclude {
+				if middleware.HasPathPrefix(c.Path(), prefix) {
+					return true
+				}
+			}
+
+			return false
+		},
+		Retention: i.Retention,
+		RetentionGenerator: func(c *fiber.Ctx) time.Duration {
+			for _, route := range routes {
+				if middleware.HasPathPrefix(c.Path(), route) {
+					return i.Routes.Get(route)
+				}
+			}
+
+			return i.Retention
+		},
+		KeyGenerator: i.KeyGenerator,
+		Storage:      i.Storage,
+	})
+}
+
+// Validate makes Idempotency validatable by implementing [validation.Validatable] interface.
+func (i *Idempotency) Validate() error {
+	return validation.ValidateStruct(i,
+		validation.Field(&i.Retention, validation.When(i.Enabled, validation.Required, validation.Min(time.Second))),
+	)
+}
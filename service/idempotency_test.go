@@ -0,0 +1,181 @@
+package service
+
+import (
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/storage/memory"
+	"github.com/leliuga/cdk/service/middleware/idempotency"
+	"github.com/leliuga/cdk/service/middleware/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency(t *testing.T) {
+	calls := 0
+	started, release := make(chan struct{}), make(chan struct{})
+	i := NewIdempotency()
+	i.Enabled = true
+	i.Routes.Set("/events", 0)
+	assert.Nil(t, i.Validate())
+
+	app := fiber.New()
+	app.Use(i.Handler())
+	app.Post("/invoices", func(c *fiber.Ctx) error {
+		calls++
+		c.Set(fiber.HeaderLocation, "/invoices/"+strconv.Itoa(calls))
+
+		return c.Status(fiber.StatusCreated).SendString(strconv.Itoa(calls))
+	})
+	app.Post("/events", func(c *fiber.Ctx) error {
+		calls++
+
+		return c.SendString(strconv.Itoa(calls))
+	})
+	app.Post("/payments", func(c *fiber.Ctx) error {
+		started <- struct{}{}
+		<-release
+
+		return c.SendString("paid")
+	})
+
+	request := func(path, key, body string) (int, string, string) {
+		req := httptest.NewRequest(fiber.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(idempotency.HeaderIdempotencyKey, key)
+
+		res, err := app.Test(req, -1)
+		assert.Nil(t, err)
+		b, _ := io.ReadAll(res.Body)
+
+		return res.StatusCode, string(b), res.Header.Get(idempotency.HeaderIdempotentReplayed)
+	}
+
+	tests := []struct {
+		tag      string
+		path     string
+		key      string
+		body     string
+		status   int
+		response string
+		replayed string
+	}{
+		{"t0", "/invoices", "k1", `{"amount":10}`, fiber.StatusCreated, "1", ""},
+		{"t1", "/invoices", "k1", `{"amount":10}`, fiber.StatusCreated, "1", "true"},
+		{"t2", "/invoices", "k1", `{"amount":20}`, fiber.StatusUnprocessableEntity, idempotency.ErrKeyReused.Message, ""},
+		{"t3", "/invoices", "k2", `{"amount":10}`, fiber.StatusCreated, "2", ""},
+		{"t4", "/invoices", "", `{"amount":10}`, fiber.StatusCreated, "3", ""},
+		{"t5", "/events", "k1", `{}`, fiber.StatusOK, "4", ""},
+		{"t6", "/events", "k1", `{}`, fiber.StatusOK, "5", ""},
+		{"t7", "/invoices", strings.Repeat("k", idempotency.MaxKeyLength+1), `{}`, fiber.StatusBadRequest, idempotency.ErrKeyTooLong.Message, ""},
+	}
+
+	for _, test := range tests {
+		status, response, replayed := request(test.path, test.key, test.body)
+		assert.Equal(t, test.status, status, test.tag)
+		assert.Equal(t, test.response, response, test.tag)
+		assert.Equal(t, test.replayed, replayed, test.tag)
+	}
+
+	done := make(chan int)
+	go func() {
+		status, _, _ := request("/payments", "k1", `{}`)
+		done <- status
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the payment request is not started")
+	}
+
+	status, response, _ := request("/payments", "k1", `{}`)
+	assert.Equal(t, fiber.StatusConflict, status)
+	assert.Equal(t, idempotency.ErrKeyInFlight.Message, response)
+
+	close(release)
+	assert.Equal(t, fiber.StatusOK, <-done)
+
+	status, response, replayed := request("/payments", "k1", `{}`)
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, "paid", response)
+	assert.Equal(t, "true", replayed)
+}
+
+func TestIdempotencyCallers(t *testing.T) {
+	calls := 0
+	i := NewIdempotency()
+	i.Enabled = true
+	i.Storage = &atomicStorage{Storage: memory.New(), mutex: &sync.Mutex{}}
+	assert.Nil(t, i.Validate())
+
+	app := fiber.New()
+	app.Use(i.Handler())
+	app.Post("/invoices", func(c *fiber.Ctx) error {
+		calls++
+
+		return c.Status(fiber.StatusCreated).SendString(c.Get(fiber.HeaderAuthorization) + " " + strconv.Itoa(calls))
+	})
+
+	tests := []struct {
+		tag           string
+		authorization string
+		response      string
+		replayed      string
+	}{
+		{"t0", "Bearer a", "Bearer a 1", ""},
+		{"t1", "Bearer b", "Bearer b 2", ""},
+		{"t2", "Bearer a", "Bearer a 1", "true"},
+		{"t3", "", " 3", ""},
+		{"t4", "", " 3", "true"},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(fiber.MethodPost, "/invoices", strings.NewReader(`{}`))
+		req.Header.Set(idempotency.HeaderIdempotencyKey, "k1")
+		if test.authorization != "" {
+			req.Header.Set(fiber.HeaderAuthorization, test.authorization)
+		}
+
+		res, err := app.Test(req)
+		assert.Nil(t, err, test.tag)
+		b, _ := io.ReadAll(res.Body)
+		assert.Equal(t, fiber.StatusCreated, res.StatusCode, test.tag)
+		assert.Equal(t, test.response, string(b), test.tag)
+		assert.Equal(t, test.replayed, res.Header.Get(idempotency.HeaderIdempotentReplayed), test.tag)
+	}
+}
+
+func TestIdempotencyRequestHeaders(t *testing.T) {
+	requests := 0
+	i := NewIdempotency()
+	i.Enabled = true
+	assert.Nil(t, i.Validate())
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		requests++
+		c.Set(fiber.HeaderXRequestID, strconv.Itoa(requests))
+		c.Set(ratelimit.HeaderRemaining, strconv.Itoa(100-requests))
+
+		return c.Next()
+	}, i.Handler())
+	app.Post("/invoices", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusCreated).SendString("created")
+	})
+
+	for n, replayed := range []string{"", "true"} {
+		req := httptest.NewRequest(fiber.MethodPost, "/invoices", strings.NewReader(`{}`))
+		req.Header.Set(idempotency.HeaderIdempotencyKey, "k1")
+
+		res, err := app.Test(req)
+		assert.Nil(t, err)
+		assert.Equal(t, replayed, res.Header.Get(idempotency.HeaderIdempotentReplayed))
+		assert.Equal(t, strconv.Itoa(n+1), res.Header.Get(fiber.HeaderXRequestID))
+		assert.Equal(t, strconv.Itoa(99-n), res.Header.Get(ratelimit.HeaderRemaining))
+	}
+}
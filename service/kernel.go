@@ -2,43 +2,269 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/leliuga/cdk/types"
+	"k8s.io/klog/v2"
+)
+
+var (
+	ErrComponentExists   = errors.New("the component is already registered")
+	ErrComponentNotFound = errors.New("the component is not registered")
+	ErrComponentCycle    = errors.New("the components have a dependency cycle")
+	ErrComponentBooted   = errors.New("the component is already booted")
 )
 
 // NewKernel returns a new kernel.
 func NewKernel() *Kernel {
 	return &Kernel{
-		instances: types.Map[any]{},
+		instances:   types.Map[any]{},
+		definitions: types.Map[*Definition]{},
+		components:  []*component{},
+		booted:      []*component{},
+		mutex:       &sync.Mutex{},
+		registry:    &sync.RWMutex{},
 	}
 }
 
-// Boot the kernel.
-func (k *Kernel) Boot(*Service) error {
+// Register a component with its dependencies to the kernel.
+func (k *Kernel) Register(name string, c IComponent, dependencies ...string) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if k.find(name) != nil {
+		return &ComponentError{Component: name, Op: "register", Err: ErrComponentExists}
+	}
+
+	k.components = append(k.components, &component{
+		name:         name,
+		component:    c,
+		dependencies: dependencies,
+	})
+	k.Set(name, c)
+
 	return nil
 }
 
-// Shutdown the kernel.
-func (k *Kernel) Shutdown(context.Context) error {
+// Replace a registered component, it keeps the dependencies of the replaced one. The booted components are not
+// replaced.
+func (k *Kernel) Replace(name string, c IComponent) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	found := k.find(name)
+	if found == nil {
+		return &ComponentError{Component: name, Op: "replace", Err: ErrComponentNotFound}
+	}
+
+	for _, booted := range k.booted {
+		if booted == found {
+			return &ComponentError{Component: name, Op: "replace", Err: ErrComponentBooted}
+		}
+	}
+
+	found.component = c
+	k.Set(name, c)
+
 	return nil
 }
 
+// Components returns the names of the registered components in boot order.
+func (k *Kernel) Components() ([]string, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	ordered, err := k.order()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(ordered))
+	for _, c := range ordered {
+		names = append(names, c.name)
+	}
+
+	return names, nil
+}
+
+// Boot the kernel components in dependency order with a background context.
+func (k *Kernel) Boot(s *Service) error {
+	return k.BootContext(context.Background(), s)
+}
+
+// BootContext boots the kernel components in dependency order, the booted components are shut down when one fails.
+// The components are booted without holding the kernel lock, so they may register or list the components, the
+// components registered while booting are not booted.
+func (k *Kernel) BootContext(ctx context.Context, s *Service) error {
+	k.mutex.Lock()
+	ordered, err := k.order()
+	k.mutex.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	for _, c := range ordered {
+		if err = c.component.Boot(ctx, s); err != nil {
+			return errors.Join(&ComponentError{Component: c.name, Op: "boot", Err: err}, k.Shutdown(ctx))
+		}
+
+		k.mutex.Lock()
+		k.booted = append(k.booted, c)
+		k.mutex.Unlock()
+	}
+
+	return nil
+}
+
+// Shutdown the booted kernel components in reverse boot order.
+func (k *Kernel) Shutdown(ctx context.Context) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	return k.shutdown(ctx)
+}
+
+// Reload notifies the booted components which implement IReloadable about the changed options.
+func (k *Kernel) Reload(ctx context.Context, options *Options, diff ConfigDiff) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	var errs []error
+	for _, c := range k.booted {
+		if reloadable, ok := c.component.(IReloadable); ok {
+			if err := reloadable.Reload(ctx, options, diff); err != nil {
+				errs = append(errs, &ComponentError{Component: c.name, Op: "reload", Err: err})
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // Set an instance to the kernel.
 func (k *Kernel) Set(key string, instance any) {
+	k.registry.Lock()
+	defer k.registry.Unlock()
+
 	k.instances.Set(key, instance)
 }
 
-// Get an instance from the kernel.
+// Get an instance from the kernel, a lazy singleton is created on first use. Nil is returned when the key is not
+// registered, the errors of the registered keys are logged, use Lookup or Resolve to handle them.
 func (k *Kernel) Get(key string) any {
-	return k.instances.Get(key)
+	instance, err := k.Lookup(context.Background(), key)
+	if err != nil && k.Has(key) {
+		klog.ErrorS(err, "failed to get the kernel instance", "key", key)
+	}
+
+	return instance
 }
 
 // Has an instance from the kernel.
 func (k *Kernel) Has(key string) bool {
-	return k.instances.Has(key)
+	k.registry.RLock()
+	defer k.registry.RUnlock()
+
+	return k.instances.Has(key) || k.definitions.Has(key)
 }
 
 // Instances returns all instances from the kernel.
 func (k *Kernel) Instances() types.Map[any] {
-	return k.instances
+	k.registry.RLock()
+	defer k.registry.RUnlock()
+
+	return k.instances.Clone()
+}
+
+// Error returns the error message.
+func (e *ComponentError) Error() string {
+	return fmt.Sprintf("component %s failed to %s: %s", e.Component, e.Op, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ComponentError) Unwrap() error {
+	return e.Err
+}
+
+// shutdown the booted components in reverse order and collect their errors.
+func (k *Kernel) shutdown(ctx context.Context) error {
+	var errs []error
+
+	for i := len(k.booted) - 1; i >= 0; i-- {
+		c := k.booted[i]
+		if err := c.component.Shutdown(ctx); err != nil {
+			errs = append(errs, &ComponentError{Component: c.name, Op: "shutdown", Err: err})
+		}
+	}
+
+	k.booted = k.booted[:0]
+
+	return errors.Join(errs...)
+}
+
+// order returns the components sorted topologically, ties keep the registration order.
+func (k *Kernel) order() ([]*component, error) {
+	for _, c := range k.components {
+		for _, dependency := range c.dependencies {
+			if k.find(dependency) == nil {
+				return nil, &ComponentError{Component: c.name, Op: "resolve dependency " + dependency, Err: ErrComponentNotFound}
+			}
+		}
+	}
+
+	ordered := make([]*component, 0, len(k.components))
+	visited := map[string]bool{}
+
+	for len(ordered) < len(k.components) {
+		progressed := false
+
+		for _, c := range k.components {
+			if visited[c.name] || !c.ready(visited) {
+				continue
+			}
+
+			visited[c.name] = true
+			ordered = append(ordered, c)
+			progressed = true
+		}
+
+		if !progressed {
+			var pending []string
+			for _, c := range k.components {
+				if !visited[c.name] {
+					pending = append(pending, c.name)
+				}
+			}
+
+			return nil, fmt.Errorf("%w: %s", ErrComponentCycle, strings.Join(pending, ", "))
+		}
+	}
+
+	return ordered, nil
+}
+
+// find returns the registered component with the given name.
+func (k *Kernel) find(name string) *component {
+	for _, c := range k.components {
+		if c.name == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// ready returns true if all dependencies of the component are visited.
+func (c *component) ready(visited map[string]bool) bool {
+	for _, dependency := range c.dependencies {
+		if !visited[dependency] {
+			return false
+		}
+	}
+
+	return true
 }
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingComponent struct {
+	name    string
+	events  *[]string
+	bootErr error
+}
+
+func (c *recordingComponent) Boot(context.Context, *Service) error {
+	if c.bootErr != nil {
+		return c.bootErr
+	}
+
+	*c.events = append(*c.events, "boot "+c.name)
+
+	return nil
+}
+
+func (c *recordingComponent) Shutdown(context.Context) error {
+	*c.events = append(*c.events, "shutdown "+c.name)
+
+	return nil
+}
+
+func TestKernelLifecycle(t *testing.T) {
+	var events []string
+	k := NewKernel()
+
+	assert.Nil(t, k.Register("consumer", &recordingComponent{name: "consumer", events: &events}, "db", "cache"))
+	assert.Nil(t, k.Register("cache", &recordingComponent{name: "cache", events: &events}))
+	assert.Nil(t, k.Register("db", &recordingComponent{name: "db", events: &events}))
+	assert.ErrorIs(t, k.Register("db", &recordingComponent{name: "db", events: &events}), ErrComponentExists)
+
+	names, err := k.Components()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"cache", "db", "consumer"}, names)
+
+	assert.Nil(t, k.BootContext(context.Background(), nil))
+	assert.Nil(t, k.Shutdown(context.Background()))
+	assert.Equal(t, []string{"boot cache", "boot db", "boot consumer", "shutdown consumer", "shutdown db", "shutdown cache"}, events)
+}
+
+func TestKernelBootRollback(t *testing.T) {
+	var events []string
+	k := NewKernel()
+	failure := errors.New("connection refused")
+
+	assert.Nil(t, k.Register("db", &recordingComponent{name: "db", events: &events}))
+	assert.Nil(t, k.Register("consumer", &recordingComponent{name: "consumer", events: &events, bootErr: failure}, "db"))
+
+	err := k.BootContext(context.Background(), nil)
+	assert.ErrorIs(t, err, failure)
+
+	var componentErr *ComponentError
+	assert.True(t, errors.As(err, &componentErr))
+	assert.Equal(t, "consumer", componentErr.Component)
+	assert.Equal(t, []string{"boot db", "shutdown db"}, events)
+}
+
+func TestKernelDependencyErrors(t *testing.T) {
+	var events []string
+
+	k := NewKernel()
+	assert.Nil(t, k.Register("a", &recordingComponent{name: "a", events: &events}, "b"))
+	assert.Nil(t, k.Register("b", &recordingComponent{name: "b", events: &events}, "a"))
+	assert.ErrorIs(t, k.BootContext(context.Background(), nil), ErrComponentCycle)
+
+	k = NewKernel()
+	assert.Nil(t, k.Register("a", &recordingComponent{name: "a", events: &events}, "missing"))
+	assert.ErrorIs(t, k.BootContext(context.Background(), nil), ErrComponentNotFound)
+	assert.Empty(t, events)
+}
+
+func TestKernelReplace(t *testing.T) {
+	var events []string
+	ctx := context.Background()
+	k := NewKernel()
+
+	assert.Nil(t, k.Register("consumer", &recordingComponent{name: "consumer", events: &events}, "db"))
+	assert.Nil(t, k.Register("db", &recordingComponent{name: "db", events: &events}))
+	assert.ErrorIs(t, k.Replace("cache", &recordingComponent{name: "cache", events: &events}), ErrComponentNotFound)
+
+	fake := &recordingComponent{name: "fake db", events: &events}
+	assert.Nil(t, k.Replace("db", fake))
+	assert.Equal(t, fake, k.Get("db"))
+
+	assert.Nil(t, k.BootContext(ctx, nil))
+	assert.Equal(t, []string{"boot fake db", "boot consumer"}, events)
+	assert.ErrorIs(t, k.Replace("db", fake), ErrComponentBooted)
+}
+
+type registeringComponent struct {
+	kernel *Kernel
+}
+
+func (c *registeringComponent) Boot(context.Context, *Service) error {
+	if _, err := c.kernel.Components(); err != nil {
+		return err
+	}
+
+	return c.kernel.Register("late", &registeringComponent{kernel: c.kernel})
+}
+
+func (c *registeringComponent) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestKernelBootRegisters(t *testing.T) {
+	k := NewKernel()
+	assert.Nil(t, k.Register("early", &registeringComponent{kernel: k}))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- k.BootContext(context.Background(), nil)
+	}()
+
+	select {
+	case err := <-done:
+		assert.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the kernel is deadlocked by a component which registers while booting")
+	}
+
+	names, err := k.Components()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"early", "late"}, names)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Listen address schemes
+const (
+	ListenSchemeUnix = "unix://"
+	ListenSchemeFd   = "fd://"
+)
+
+// Environment variables of the socket activation protocol
+const (
+	EnvListenPid     = "LISTEN_PID"
+	EnvListenFds     = "LISTEN_FDS"
+	EnvListenFdNames = "LISTEN_FDNAMES"
+)
+
+const (
+	DefaultSocketMode    = "0660"
+	InvalidListenAddress = "A listen address must be unix://<path>, fd:// or fd://<number|name>."
+
+	// listenFdsStart is the first file descriptor passed by the socket activation protocol.
+	listenFdsStart = 3
+)
+
+var (
+	ListenAddressRegex = regexp.MustCompile(`^(unix://.+|fd://[A-Za-z0-9_.:-]*)$`)
+	SocketModeRegex    = regexp.MustCompile(`^0?[0-7]{3}$`)
+
+	ErrListenerNotInherited = errors.New("the listener is not inherited")
+)
+
+// address returns the listen address of the service, the port on all interfaces when it is not configured.
+func (s *Service) address() string {
+	if s.ListenAddress != "" {
+		return s.ListenAddress
+	}
+
+	return fmt.Sprintf(":%d", s.Port)
+}
+
+// listen binds a listener on the given address, it is wrapped with TLS when the config is given.
+func (s *Service) listen(address string, config *tls.Config) (net.Listener, error) {
+	var (
+		listener net.Listener
+		err      error
+	)
+
+	switch {
+	case strings.HasPrefix(address, ListenSchemeUnix):
+		listener, err = listenUnix(strings.TrimPrefix(address, ListenSchemeUnix), s.SocketMode)
+	case strings.HasPrefix(address, ListenSchemeFd):
+		listener, err = inheritedListener(strings.TrimPrefix(address, ListenSchemeFd))
+	default:
+		listener, err = net.Listen(s.Network, address)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+
+	if config == nil {
+		return listener, nil
+	}
+
+	return tls.NewListener(listener, config), nil
+}
+
+// cleanup removes the unix socket of the service.
+func (s *Service) cleanup() error {
+	if !strings.HasPrefix(s.ListenAddress, ListenSchemeUnix) {
+		return nil
+	}
+
+	if err := os.Remove(strings.TrimPrefix(s.ListenAddress, ListenSchemeUnix)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove the unix socket: %w", err)
+	}
+
+	return nil
+}
+
+// listenUnix binds a unix socket with the given octal file mode, a stale socket is removed first.
+func listenUnix(path, mode string) (net.Listener, error) {
+	perm, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid socket mode %s: %w", mode, err)
+	}
+
+	if info, err := os.Stat(path); err == nil && info.Mode()&fs.ModeSocket != 0 {
+		if err = os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.Chmod(path, fs.FileMode(perm)); err != nil {
+		return nil, errors.Join(err, listener.Close())
+	}
+
+	return listener, nil
+}
+
+// inheritedListener returns the listener inherited by the socket activation protocol, it is selected by
+// the file descriptor number or name, the first one is selected when the selector is empty.
+func inheritedListener(selector string) (net.Listener, error) {
+	if pid, err := strconv.Atoi(os.Getenv(EnvListenPid)); err != nil || pid != os.Getpid() {
+		return nil, ErrListenerNotInherited
+	}
+
+	count, err := strconv.Atoi(os.Getenv(EnvListenFds))
+	if err != nil || count < 1 {
+		return nil, ErrListenerNotInherited
+	}
+
+	names := strings.Split(os.Getenv(EnvListenFdNames), ":")
+	for i := 0; i < count; i++ {
+		fd := listenFdsStart + i
+		name := ""
+		if i < len(names) {
+			name = names[i]
+		}
+
+		if selector != "" && selector != strconv.Itoa(fd) && selector != name {
+			continue
+		}
+
+		file := os.NewFile(uintptr(fd), name)
+		listener, err := net.FileListener(file)
+		if err != nil {
+			return nil, errors.Join(err, file.Close())
+		}
+
+		return listener, file.Close()
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrListenerNotInherited, selector)
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// Default values for the metadata endpoints
+const (
+	DefaultAwsMetadataURL   = "http://169.254.169.254"
+	DefaultGcpMetadataURL   = "http://metadata.google.internal"
+	DefaultAzureMetadataURL = "http://169.254.169.254"
+	DefaultDoMetadataURL    = "http://169.254.169.254"
+	AzureMetadataAPIVersion = "2021-02-01"
+)
+
+var (
+	ErrMetadataUnavailable = errors.New("the metadata endpoint is unavailable")
+)
+
+// NewAwsMetadataProbe returns a new AWS metadata probe, an empty base url uses the instance metadata service.
+func NewAwsMetadataProbe(baseURL string) *AwsMetadataProbe {
+	return &AwsMetadataProbe{BaseURL: firstNonEmpty(baseURL, DefaultAwsMetadataURL)}
+}
+
+// Provider returns the provider of the probe.
+func (p *AwsMetadataProbe) Provider() Provider {
+	return ProviderAws
+}
+
+// Probe returns the region and the availability zone of the instance using an IMDSv2 session token.
+func (p *AwsMetadataProbe) Probe(ctx context.Context, client *http.Client) (string, string, error) {
+	token, err := fetchMetadata(ctx, client, http.MethodPut, p.BaseURL+"/latest/api/token", map[string]string{
+		"X-aws-ec2-metadata-token-ttl-seconds": "60",
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	headers := map[string]string{"X-aws-ec2-metadata-token": token}
+
+	region, err := fetchMetadata(ctx, client, http.MethodGet, p.BaseURL+"/latest/meta-data/placement/region", headers)
+	if err != nil {
+		return "", "", err
+	}
+
+	zone, err := fetchMetadata(ctx, client, http.MethodGet, p.BaseURL+"/latest/meta-data/placement/availability-zone", headers)
+	if err != nil {
+		return "", "", err
+	}
+
+	return region, zone, nil
+}
+
+// NewGcpMetadataProbe returns a new GCP metadata probe, an empty base url uses the metadata server.
+func NewGcpMetadataProbe(baseURL string) *GcpMetadataProbe {
+	return &GcpMetadataProbe{BaseURL: firstNonEmpty(baseURL, DefaultGcpMetadataURL)}
+}
+
+// Provider returns the provider of the probe.
+func (p *GcpMetadataProbe) Provider() Provider {
+	return ProviderGcp
+}
+
+// Probe returns the region and the zone of the instance, the region is the zone without its suffix.
+func (p *GcpMetadataProbe) Probe(ctx context.Context, client *http.Client) (string, string, error) {
+	zone, err := fetchMetadata(ctx, client, http.MethodGet, p.BaseURL+"/computeMetadata/v1/instance/zone", map[string]string{
+		"Metadata-Flavor": "Google",
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	// The zone is returned as projects/<number>/zones/<zone>.
+	zone = zone[strings.LastIndex(zone, "/")+1:]
+	region := zone
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		region = zone[:i]
+	}
+
+	return region, zone, nil
+}
+
+// NewAzureMetadataProbe returns a new Azure metadata probe, an empty base url uses the instance metadata service.
+func NewAzureMetadataProbe(baseURL string) *AzureMetadataProbe {
+	return &AzureMetadataProbe{BaseURL: firstNonEmpty(baseURL, DefaultAzureMetadataURL)}
+}
+
+// Provider returns the provider of the probe.
+func (p *AzureMetadataProbe) Provider() Provider {
+	return ProviderAzure
+}
+
+// Probe returns the location and the zone of the virtual machine, the response must identify the virtual machine
+// and its location.
+func (p *AzureMetadataProbe) Probe(ctx context.Context, client *http.Client) (string, string, error) {
+	var compute struct {
+		VMID     string `json:"vmId"`
+		Location string `json:"location"`
+		Zone     string `json:"zone"`
+	}
+
+	body, err := fetchMetadata(ctx, client, http.MethodGet, p.BaseURL+"/metadata/instance/compute?api-version="+AzureMetadataAPIVersion, map[string]string{
+		"Metadata": "true",
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	if err = json.Unmarshal([]byte(body), &compute); err != nil {
+		return "", "", fmt.Errorf("%w: %s", ErrMetadataUnavailable, err)
+	}
+
+	if compute.VMID == "" || compute.Location == "" {
+		return "", "", fmt.Errorf("%w: the compute metadata has no vmId or location", ErrMetadataUnavailable)
+	}
+
+	return compute.Location, compute.Zone, nil
+}
+
+// NewDoMetadataProbe returns a new Digital Ocean metadata probe, an empty base url uses the metadata service.
+func NewDoMetadataProbe(baseURL string) *DoMetadataProbe {
+	return &DoMetadataProbe{BaseURL: firstNonEmpty(baseURL, DefaultDoMetadataURL)}
+}
+
+// Provider returns the provider of the probe.
+func (p *DoMetadataProbe) Provider() Provider {
+	return ProviderDo
+}
+
+// Probe returns the region of the droplet, Digital Ocean has no zones.
+func (p *DoMetadataProbe) Probe(ctx context.Context, client *http.Client) (string, string, error) {
+	region, err := fetchMetadata(ctx, client, http.MethodGet, p.BaseURL+"/metadata/v1/region", nil)
+	if err != nil {
+		return "", "", err
+	}
+
+	return region, "", nil
+}
+
+// fetchMetadata returns the trimmed body of the metadata endpoint, a non 200 status or an empty body is an error.
+func fetchMetadata(ctx context.Context, client *http.Client, method, url string, headers map[string]string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrMetadataUnavailable, err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 64<<10))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrMetadataUnavailable, err)
+	}
+
+	value := strings.TrimSpace(string(body))
+	if res.StatusCode != http.StatusOK || value == "" {
+		return "", fmt.Errorf("%w: %s %s", ErrMetadataUnavailable, url, res.Status)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/metrics"
+)
+
+// MetricsHandler returns a handler which serves the metrics in the Prometheus text exposition format.
+func (s *Service) MetricsHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, metrics.ContentType)
+		c.Set(fiber.HeaderCacheControl, "no-store")
+
+		return s.Metrics.Write(c)
+	}
+}
+
+// newMetrics returns a new metrics registry with the runtime metrics registered.
+func newMetrics() *metrics.Registry {
+	registry := metrics.NewRegistry()
+	registry.RegisterRuntimeMetrics()
+
+	return registry
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/http"
+	"github.com/leliuga/cdk/metrics"
+	requestmetrics "github.com/leliuga/cdk/service/middleware/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	s := NewService(NewOptions())
+	s.Use(requestmetrics.New(requestmetrics.Config{Registry: s.Metrics}))
+	s.Get("/users/:id", func(c *fiber.Ctx) error {
+		return c.SendString("user")
+	})
+	s.Post("/users", func(c *fiber.Ctx) error {
+		return NewBindError(http.StatusUnsupportedMediaType, "unsupported", nil)
+	})
+	s.Get(DefaultPathMetrics, s.MetricsHandler())
+
+	_, err := s.Test(httptest.NewRequest(fiber.MethodGet, "/users/1", nil))
+	assert.Nil(t, err)
+
+	_, err = s.Test(httptest.NewRequest(fiber.MethodPost, "/users", nil))
+	assert.Nil(t, err)
+
+	resp, err := s.Test(httptest.NewRequest(fiber.MethodGet, DefaultPathMetrics, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, metrics.ContentType, resp.Header.Get(fiber.HeaderContentType))
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), `http_requests_total{method="GET",route="/users/:id",status="200"} 1`)
+	assert.Contains(t, string(body), `http_requests_total{method="POST",route="/users",status="415"} 1`)
+	assert.Contains(t, string(body), "go_goroutines")
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/gofiber/fiber/v2/middleware/etag"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/leliuga/cdk/service/middleware"
+	requestmetrics "github.com/leliuga/cdk/service/middleware/metrics"
+	"github.com/leliuga/cdk/types"
+)
+
+// Names of the built-in middlewares
+const (
+	MiddlewareRecover     = "recover"
+	MiddlewareMetrics     = "metrics"
+	MiddlewareCompress    = "compress"
+	MiddlewareRequestID   = "requestid"
+	MiddlewareTracing     = "tracing"
+	MiddlewareAccessLog   = "accesslog"
+	MiddlewareSecurity    = "security"
+	MiddlewareCORS        = "cors"
+	MiddlewareRateLimit   = "ratelimit"
+	MiddlewareIdempotency = "idempotency"
+	MiddlewareETag        = "etag"
+	MiddlewareCache       = "cache"
+	MiddlewareScope       = "scope"
+)
+
+var (
+	ErrMiddlewareNotFound = errors.New("the middleware is not registered")
+
+	// DefaultMiddlewares is the default order of the middlewares, the cache follows the etag so the cached responses
+	// are revalidated.
+	DefaultMiddlewares = []string{
+		MiddlewareRecover,
+		MiddlewareMetrics,
+		MiddlewareCompress,
+		MiddlewareRequestID,
+		MiddlewareTracing,
+		MiddlewareAccessLog,
+		MiddlewareSecurity,
+		MiddlewareCORS,
+		MiddlewareRateLimit,
+		MiddlewareIdempotency,
+		MiddlewareETag,
+		MiddlewareCache,
+		MiddlewareScope,
+	}
+
+	middlewares = types.Map[MiddlewareConstructor]{
+		MiddlewareRecover: func(*Service) fiber.Handler {
+			return recover.New()
+		},
+		MiddlewareMetrics: func(s *Service) fiber.Handler {
+			return requestmetrics.New(requestmetrics.Config{
+				Next: func(c *fiber.Ctx) bool {
+					return middleware.HasPathPrefix(c.Path(), DefaultPathMonitoring)
+				},
+				Registry: s.Metrics,
+			})
+		},
+		MiddlewareCompress: func(*Service) fiber.Handler {
+			return compress.New(compress.Config{
+				Level: compress.LevelBestSpeed,
+			})
+		},
+		MiddlewareRequestID: func(*Service) fiber.Handler {
+			return requestid.New()
+		},
+		MiddlewareTracing: func(s *Service) fiber.Handler {
+			return s.Tracing.Handler(s.Tracer)
+		},
+		MiddlewareAccessLog: func(s *Service) fiber.Handler {
+			return s.AccessLog.Handler()
+		},
+		MiddlewareSecurity: func(s *Service) fiber.Handler {
+			return s.Security.Handler()
+		},
+		MiddlewareCORS: func(s *Service) fiber.Handler {
+			return s.Security.CORSHandler()
+		},
+		MiddlewareRateLimit: func(s *Service) fiber.Handler {
+			return s.RateLimit.Handler()
+		},
+		MiddlewareIdempotency: func(s *Service) fiber.Handler {
+			return s.Idempotency.Handler()
+		},
+		MiddlewareETag: func(*Service) fiber.Handler {
+			return etag.New()
+		},
+		MiddlewareCache: func(s *Service) fiber.Handler {
+			return s.Cache.Handler()
+		},
+		MiddlewareScope: func(*Service) fiber.Handler {
+			return RequestScopeHandler()
+		},
+	}
+	middlewaresMutex = &sync.RWMutex{}
+)
+
+// RegisterMiddleware registers the named middleware constructor, the middlewares are referenced by name in the
+// Middlewares of the options. A registered name is replaced.
+func RegisterMiddleware(name string, constructor MiddlewareConstructor) {
+	middlewaresMutex.Lock()
+	defer middlewaresMutex.Unlock()
+
+	middlewares.Set(name, constructor)
+}
+
+// RegisteredMiddlewares returns the sorted names of the registered middlewares.
+func RegisteredMiddlewares() []string {
+	middlewaresMutex.RLock()
+	defer middlewaresMutex.RUnlock()
+
+	names := middlewares.Keys()
+	sort.Strings(names)
+
+	return names
+}
+
+// handlers returns the middlewares of the service in the configured order.
+func (s *Service) handlers() ([]any, error) {
+	middlewaresMutex.RLock()
+	defer middlewaresMutex.RUnlock()
+
+	handlers := make([]any, 0, len(s.Middlewares))
+	for _, name := range s.Middlewares {
+		constructor, ok := middlewares[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMiddlewareNotFound, name)
+		}
+
+		handlers = append(handlers, constructor(s))
+	}
+
+	return handlers, nil
+}
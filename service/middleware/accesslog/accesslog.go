@@ -0,0 +1,71 @@
+package accesslog
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/service/middleware"
+	"k8s.io/klog/v2"
+)
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	cfg := configDefault(config...)
+
+	return func(c *fiber.Ctx) error {
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		for _, prefix := range cfg.Exclude {
+			if middleware.HasPathPrefix(c.Path(), prefix) {
+				return c.Next()
+			}
+		}
+
+		start := time.Now()
+		err := c.Next()
+
+		status := middleware.Status(c, err)
+
+		if status < fiber.StatusInternalServerError && cfg.SampleRate < 1 && rand.Float64() >= cfg.SampleRate {
+			return err
+		}
+
+		entry := &Entry{
+			Time:      start.UTC().Format(time.RFC3339Nano),
+			Method:    c.Method(),
+			Route:     c.Route().Path,
+			Path:      c.Path(),
+			Status:    status,
+			Latency:   time.Since(start).Seconds(),
+			Bytes:     len(c.Response().Body()),
+			IP:        c.IP(),
+			RequestID: c.GetRespHeader(cfg.RequestIDHeader),
+		}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+
+		if cfg.JSON {
+			klog.InfoS("request", "entry", entry)
+
+			return err
+		}
+
+		klog.InfoS("request",
+			"method", entry.Method,
+			"route", entry.Route,
+			"path", entry.Path,
+			"status", entry.Status,
+			"latency", entry.Latency,
+			"bytes", entry.Bytes,
+			"ip", entry.IP,
+			"request_id", entry.RequestID,
+			"error", entry.Error,
+		)
+
+		return err
+	}
+}
@@ -0,0 +1,33 @@
+package accesslog
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// ConfigDefault is the default config
+var (
+	ConfigDefault = Config{
+		Next:            nil,
+		SampleRate:      1,
+		RequestIDHeader: fiber.HeaderXRequestID,
+	}
+)
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	c := config[0]
+
+	if c.SampleRate <= 0 || c.SampleRate > 1 {
+		c.SampleRate = ConfigDefault.SampleRate
+	}
+
+	if c.RequestIDHeader == "" {
+		c.RequestIDHeader = ConfigDefault.RequestIDHeader
+	}
+
+	return c
+}
@@ -0,0 +1,51 @@
+package accesslog
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type (
+	// Config defines the config for middleware.
+	Config struct {
+		// Next defines a function to skip this middleware when returned true.
+		//
+		// Optional. Default: nil
+		Next func(c *fiber.Ctx) bool
+
+		// Exclude is the list of path prefixes which are not logged, they match whole path segments.
+		//
+		// Optional. Default: nil
+		Exclude []string
+
+		// SampleRate is the fraction of the successful requests which are logged,
+		// the requests which fail with a server error are always logged.
+		//
+		// Optional. Default: 1
+		SampleRate float64
+
+		// RequestIDHeader is the header key where to get the request ID from the response.
+		//
+		// Optional. Default: "X-Request-ID"
+		RequestIDHeader string
+
+		// JSON logs the entry as one structured value instead of a key per field, it is rendered as a json object
+		// by klog.
+		//
+		// Optional. Default: false
+		JSON bool
+	}
+
+	// Entry represents a logged request.
+	Entry struct {
+		Time      string  `json:"time"`
+		Method    string  `json:"method"`
+		Route     string  `json:"route"`
+		Path      string  `json:"path"`
+		Status    int     `json:"status"`
+		Latency   float64 `json:"latency"`
+		Bytes     int     `json:"bytes"`
+		IP        string  `json:"ip"`
+		RequestID string  `json:"request_id,omitempty"`
+		Error     string  `json:"error,omitempty"`
+	}
+)
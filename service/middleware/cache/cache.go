@@ -0,0 +1,339 @@
+package cache
+
+import (
+	"encoding/hex"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/leliuga/cdk/database"
+	"github.com/leliuga/cdk/service/middleware/ratelimit"
+)
+
+const (
+	// HeaderCacheStatus tells whether the response is served from the cache, its value is hit or miss. It is not set
+	// on the responses which are not cacheable.
+	HeaderCacheStatus = "X-Cache"
+
+	// localsTags is the key of the tags of the response in the locals of the request.
+	localsTags = "cache.tags"
+)
+
+var (
+	// skippedHeaders are the response headers which are not cached, the request id and the rate limit state describe
+	// the request which stored the response.
+	skippedHeaders = []string{
+		fiber.HeaderDate, fiber.HeaderContentLength, fiber.HeaderSetCookie, fiber.HeaderAge, HeaderCacheStatus,
+		fiber.HeaderXRequestID, ratelimit.HeaderLimit, ratelimit.HeaderRemaining, ratelimit.HeaderReset,
+		ratelimit.HeaderPolicy, ratelimit.HeaderRetryAfter,
+	}
+)
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	cfg := configDefault(config...)
+	mutex := &sync.Mutex{}
+
+	return func(c *fiber.Ctx) error {
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		if !slices.Contains(cfg.Methods, c.Method()) {
+			return c.Next()
+		}
+
+		// The responses to the requests with cookies are personal and they are not keyed by the cookies.
+		request := parseDirectives(c.Get(fiber.HeaderCacheControl))
+		if request.has("no-store") || len(c.Request().Header.Peek(fiber.HeaderCookie)) > 0 {
+			return c.Next()
+		}
+
+		expiration := cfg.ExpirationGenerator(c)
+		if expiration <= 0 {
+			return c.Next()
+		}
+
+		key := cfg.Prefix + cfg.KeyGenerator(c)
+
+		// The clients which ask to revalidate get a fresh response which replaces the cached one, the authorized
+		// requests are never served from the cache as their responses may depend on the credentials.
+		authorized := len(c.Request().Header.Peek(fiber.HeaderAuthorization)) > 0
+		if !authorized && !request.has("no-cache") && request["max-age"] != "0" {
+			e, err := load(cfg.Storage, key, c)
+			if err != nil {
+				return err
+			}
+
+			if e != nil {
+				e.write(c)
+
+				return nil
+			}
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		if ttl, ok := cacheable(c, expiration, authorized); ok {
+			c.Set(HeaderCacheStatus, "miss")
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			return store(cfg.Storage, cfg.Prefix, key, c, ttl)
+		}
+
+		return nil
+	}
+}
+
+// Tag tags the response of the request, the cached responses are invalidated by their tags.
+func Tag(c *fiber.Ctx, tags ...string) {
+	existing, _ := c.Locals(localsTags).([]string)
+	c.Locals(localsTags, append(existing, tags...))
+}
+
+// Invalidate removes the cached responses which are tagged with any of the given tags.
+func Invalidate(storage database.IKeyValue, prefix string, tags ...string) error {
+	for _, tag := range tags {
+		keys, err := loadTag(storage, prefix, tag)
+		if err != nil {
+			return err
+		}
+
+		for key := range keys {
+			if err = storage.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		if err = storage.Delete(tagKey(prefix, tag)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// load returns the cached variant of the response for the request, it is nil when it is not cached.
+func load(storage database.IKeyValue, key string, c *fiber.Ctx) (*entry, error) {
+	m := &manifest{}
+	if found, err := get(storage, key, m); err != nil || !found {
+		return nil, err
+	}
+
+	e := &entry{}
+	if found, err := get(storage, m.variant(key, c), e); err != nil || !found {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// store caches the response under the variant of the request and indexes it by its tags.
+func store(storage database.IKeyValue, prefix, key string, c *fiber.Ctx, ttl time.Duration) error {
+	m := &manifest{}
+	found, err := get(storage, key, m)
+	if err != nil {
+		return err
+	}
+
+	vary := parseVary(string(c.Response().Header.Peek(fiber.HeaderVary)))
+	if !found || !slices.Equal(m.Vary, vary) {
+		m = &manifest{ID: strconv.FormatInt(time.Now().UnixNano(), 36), Vary: vary}
+	}
+
+	e := &entry{
+		Status:  c.Response().StatusCode(),
+		Headers: [][2]string{},
+		Body:    append([]byte{}, c.Response().Body()...),
+		Stored:  time.Now().Unix(),
+	}
+
+	c.Response().Header.VisitAll(func(k, v []byte) {
+		if name := string(k); !skipped(name) {
+			e.Headers = append(e.Headers, [2]string{name, string(v)})
+		}
+	})
+
+	if err = set(storage, m.variant(key, c), e, ttl); err != nil {
+		return err
+	}
+
+	if err = set(storage, key, m, ttl); err != nil {
+		return err
+	}
+
+	tags, _ := c.Locals(localsTags).([]string)
+	for _, tag := range tags {
+		if err = index(storage, prefix, tag, key, ttl); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cacheable returns the time to live of the response, the responses which are not successful, set cookies or are not
+// shared by the Cache-Control and Vary headers are not cached. The responses to the authorized requests are cached
+// only when they are explicitly shared by the public, s-maxage or must-revalidate directives, see RFC 9111 section 3.5.
+func cacheable(c *fiber.Ctx, expiration time.Duration, authorized bool) (time.Duration, bool) {
+	if c.Response().StatusCode() != fiber.StatusOK || len(c.Response().Header.Peek(fiber.HeaderSetCookie)) > 0 {
+		return 0, false
+	}
+
+	if string(c.Response().Header.Peek(fiber.HeaderVary)) == "*" {
+		return 0, false
+	}
+
+	response := parseDirectives(string(c.Response().Header.Peek(fiber.HeaderCacheControl)))
+	if response.has("no-store") || response.has("no-cache") || response.has("private") {
+		return 0, false
+	}
+
+	if authorized && !response.has("public") && !response.has("s-maxage") && !response.has("must-revalidate") {
+		return 0, false
+	}
+
+	for _, directive := range []string{"s-maxage", "max-age"} {
+		if value, ok := response[directive]; ok {
+			seconds, err := strconv.Atoi(value)
+			if err != nil || seconds <= 0 {
+				return 0, false
+			}
+
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+
+	return expiration, true
+}
+
+// write writes the cached response with its age.
+func (e *entry) write(c *fiber.Ctx) {
+	c.Status(e.Status)
+	for _, header := range e.Headers {
+		c.Response().Header.Set(header[0], header[1])
+	}
+
+	c.Set(fiber.HeaderAge, strconv.FormatInt(time.Now().Unix()-e.Stored, 10))
+	c.Set(HeaderCacheStatus, "hit")
+	c.Response().SetBodyRaw(e.Body)
+}
+
+// variant returns the key of the variant of the request, it depends on the request headers named by Vary.
+func (m *manifest) variant(key string, c *fiber.Ctx) string {
+	h := fnv.New64a()
+	for _, name := range m.Vary {
+		_, _ = h.Write([]byte(name + ":" + c.Get(name) + "\n"))
+	}
+
+	return key + "#" + m.ID + "#" + hex.EncodeToString(h.Sum(nil))
+}
+
+// has returns true if the directive is present.
+func (d directives) has(name string) bool {
+	_, ok := d[name]
+
+	return ok
+}
+
+// parseDirectives parses the Cache-Control header.
+func parseDirectives(value string) directives {
+	d := directives{}
+	for _, directive := range strings.Split(value, ",") {
+		name, argument, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if name != "" {
+			d[utils.ToLower(name)] = strings.Trim(argument, `"`)
+		}
+	}
+
+	return d
+}
+
+// parseVary returns the canonical names of the request headers of the Vary header.
+func parseVary(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, utils.ToLower(name))
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// index adds the key to the index of the tag, the index expires with the last of its keys.
+func index(storage database.IKeyValue, prefix, tag, key string, ttl time.Duration) error {
+	keys, err := loadTag(storage, prefix, tag)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	keys[key] = now.Add(ttl).UnixNano()
+
+	last := now.UnixNano()
+	for k, expires := range keys {
+		if expires <= now.UnixNano() {
+			delete(keys, k)
+		} else if expires > last {
+			last = expires
+		}
+	}
+
+	return set(storage, tagKey(prefix, tag), keys, time.Duration(last-now.UnixNano()))
+}
+
+// loadTag returns the keys of the responses tagged with the tag and their expiration in unix nanoseconds.
+func loadTag(storage database.IKeyValue, prefix, tag string) (map[string]int64, error) {
+	keys := map[string]int64{}
+	if _, err := get(storage, tagKey(prefix, tag), &keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// tagKey returns the key of the index of the tag.
+func tagKey(prefix, tag string) string {
+	return prefix + "tag:" + tag
+}
+
+// get unmarshals the stored value of the key, it returns false when the key does not exist.
+func get(storage database.IKeyValue, key string, value any) (bool, error) {
+	b, err := storage.Get(key)
+	if err != nil || len(b) == 0 {
+		return false, err
+	}
+
+	return true, json.Unmarshal(b, value)
+}
+
+// set marshals and stores the value of the key.
+func set(storage database.IKeyValue, key string, value any, ttl time.Duration) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return storage.Set(key, b, ttl)
+}
+
+// skipped returns true when the response header is not cached, the names are compared case-insensitively as the
+// headers are normalized.
+func skipped(name string) bool {
+	return slices.ContainsFunc(skippedHeaders, func(header string) bool {
+		return strings.EqualFold(header, name)
+	})
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/storage/memory"
+)
+
+// ConfigDefault is the default config
+var (
+	ConfigDefault = Config{
+		Next:       nil,
+		Expiration: 1 * time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return c.Method() + " " + c.OriginalURL()
+		},
+		Methods: []string{fiber.MethodGet, fiber.MethodHead},
+		Prefix:  "cache:",
+	}
+)
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	c := ConfigDefault
+	if len(config) > 0 {
+		c = config[0]
+	}
+
+	if c.Expiration <= 0 {
+		c.Expiration = ConfigDefault.Expiration
+	}
+
+	if c.ExpirationGenerator == nil {
+		expiration := c.Expiration
+		c.ExpirationGenerator = func(*fiber.Ctx) time.Duration {
+			return expiration
+		}
+	}
+
+	if c.KeyGenerator == nil {
+		c.KeyGenerator = ConfigDefault.KeyGenerator
+	}
+
+	if len(c.Methods) == 0 {
+		c.Methods = ConfigDefault.Methods
+	}
+
+	if c.Storage == nil {
+		c.Storage = memory.New()
+	}
+
+	if c.Prefix == "" {
+		c.Prefix = ConfigDefault.Prefix
+	}
+
+	return c
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/database"
+)
+
+type (
+	// Config defines the config for middleware.
+	Config struct {
+		// Next defines a function to skip this middleware when returned true.
+		//
+		// Optional. Default: nil
+		Next func(c *fiber.Ctx) bool
+
+		// Expiration is the time to live of the cached responses,
+		// the max-age and s-maxage directives of the response take precedence.
+		//
+		// Optional. Default: 1 * time.Minute
+		Expiration time.Duration
+
+		// ExpirationGenerator returns the time to live of the response of the request, 0 does not cache it.
+		//
+		// Optional. Default: returns Expiration
+		ExpirationGenerator func(c *fiber.Ctx) time.Duration
+
+		// KeyGenerator returns the key of the cached response, the requests with cookies are not cached and the
+		// authorized requests are not served from the cache so the key holds no credential.
+		//
+		// Optional. Default: the method and the original url
+		KeyGenerator func(c *fiber.Ctx) string
+
+		// Methods are the request methods which are cached.
+		//
+		// Optional. Default: GET, HEAD
+		Methods []string
+
+		// Storage stores the cached responses, the replicas share them through a remote storage.
+		//
+		// Optional. Default: an in-memory storage
+		Storage database.IKeyValue
+
+		// Prefix is prepended to the keys in the Storage.
+		//
+		// Optional. Default: "cache:"
+		Prefix string
+	}
+
+	// entry represents a cached response.
+	entry struct {
+		Status  int         `json:"status"`
+		Headers [][2]string `json:"headers"`
+		Body    []byte      `json:"body"`
+		Stored  int64       `json:"stored"`
+	}
+
+	// manifest represents the variants of a cached response, the variants of a previous manifest are unreachable.
+	manifest struct {
+		ID   string   `json:"id"`
+		Vary []string `json:"vary"`
+	}
+
+	// directives represents the parsed Cache-Control header.
+	directives map[string]string
+)
@@ -0,0 +1,66 @@
+package idempotency
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/storage/memory"
+)
+
+// ConfigDefault is the default config
+var (
+	ConfigDefault = Config{
+		Next:         nil,
+		Methods:      []string{fiber.MethodPost, fiber.MethodPatch},
+		KeyHeader:    HeaderIdempotencyKey,
+		KeyGenerator: KeyByCaller(),
+		Retention:    24 * time.Hour,
+		LockTimeout:  1 * time.Minute,
+		Prefix:       "idempotency:",
+	}
+)
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	c := ConfigDefault
+	if len(config) > 0 {
+		c = config[0]
+	}
+
+	if len(c.Methods) == 0 {
+		c.Methods = ConfigDefault.Methods
+	}
+
+	if c.KeyHeader == "" {
+		c.KeyHeader = ConfigDefault.KeyHeader
+	}
+
+	if c.KeyGenerator == nil {
+		c.KeyGenerator = ConfigDefault.KeyGenerator
+	}
+
+	if c.Retention <= 0 {
+		c.Retention = ConfigDefault.Retention
+	}
+
+	if c.RetentionGenerator == nil {
+		retention := c.Retention
+		c.RetentionGenerator = func(*fiber.Ctx) time.Duration {
+			return retention
+		}
+	}
+
+	if c.LockTimeout <= 0 {
+		c.LockTimeout = ConfigDefault.LockTimeout
+	}
+
+	if c.Storage == nil {
+		c.Storage = memory.New()
+	}
+
+	if c.Prefix == "" {
+		c.Prefix = ConfigDefault.Prefix
+	}
+
+	return c
+}
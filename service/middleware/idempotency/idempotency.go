@@ -0,0 +1,236 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/database"
+	"github.com/leliuga/cdk/service/middleware/ratelimit"
+)
+
+const (
+	// HeaderIdempotencyKey is the request header of the idempotency key.
+	HeaderIdempotencyKey = "Idempotency-Key"
+
+	// HeaderIdempotentReplayed tells that the response is replayed from the first request of the key.
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	// MaxKeyLength is the max length of the idempotency key.
+	MaxKeyLength = 255
+
+	// maxAttempts is the number of times the lock of a key is swapped before the request fails with ErrKeyInFlight.
+	maxAttempts = 8
+)
+
+var (
+	// ErrKeyTooLong is returned when the idempotency key is longer than MaxKeyLength.
+	ErrKeyTooLong = fiber.NewError(fiber.StatusBadRequest, "The idempotency key is too long")
+
+	// ErrKeyInFlight is returned when the request of the idempotency key is still in flight.
+	ErrKeyInFlight = fiber.NewError(fiber.StatusConflict, "A request with the same idempotency key is in flight")
+
+	// ErrKeyReused is returned when the idempotency key is reused with a different payload.
+	ErrKeyReused = fiber.NewError(fiber.StatusUnprocessableEntity, "The idempotency key is reused with a different payload")
+
+	// skippedHeaders are the response headers which are not replayed, the request id and the rate limit state describe
+	// the first request of the key.
+	skippedHeaders = []string{
+		fiber.HeaderDate, fiber.HeaderContentLength, fiber.HeaderSetCookie, HeaderIdempotentReplayed,
+		fiber.HeaderXRequestID, ratelimit.HeaderLimit, ratelimit.HeaderRemaining, ratelimit.HeaderReset,
+		ratelimit.HeaderPolicy, ratelimit.HeaderRetryAfter,
+	}
+)
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	cfg := configDefault(config...)
+	mutex := &sync.Mutex{}
+
+	return func(c *fiber.Ctx) error {
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		if !slices.Contains(cfg.Methods, c.Method()) {
+			return c.Next()
+		}
+
+		key := c.Get(cfg.KeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+
+		if len(key) > MaxKeyLength {
+			return ErrKeyTooLong
+		}
+
+		retention := cfg.RetentionGenerator(c)
+		if retention <= 0 {
+			return c.Next()
+		}
+
+		key = cfg.Prefix + cfg.KeyGenerator(c) + " " + c.Method() + " " + c.Path() + " " + key
+		fingerprint := fingerprint(c)
+
+		r, err := acquire(cfg.Storage, mutex, key, fingerprint, cfg.LockTimeout)
+		if err != nil {
+			return err
+		}
+
+		if r != nil {
+			r.write(c)
+
+			return nil
+		}
+
+		// The lock is released on failure, the client may retry the request with the same key.
+		if err = c.Next(); err != nil || c.Response().StatusCode() >= fiber.StatusInternalServerError {
+			return release(cfg.Storage, key, err)
+		}
+
+		return set(cfg.Storage, key, response(c, fingerprint), retention)
+	}
+}
+
+// acquire locks the key for the request, it returns the stored response of the key or an error when the key is
+// locked or was used with a different payload. The lock is set only if absent when the storage implements
+// database.IAtomicKeyValue, otherwise the locks are exclusive per instance only and the replicas sharing the storage
+// may run the same request twice.
+func acquire(storage database.IKeyValue, mutex *sync.Mutex, key, fingerprint string, timeout time.Duration) (*record, error) {
+	swapper, atomic := storage.(database.IAtomicKeyValue)
+	if !atomic {
+		mutex.Lock()
+		defer mutex.Unlock()
+	}
+
+	lock, err := json.Marshal(&record{Fingerprint: fingerprint, Locked: true})
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		r := &record{}
+		found, err := get(storage, key, r)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			if r.Fingerprint != fingerprint {
+				return nil, ErrKeyReused
+			}
+
+			if r.Locked {
+				return nil, ErrKeyInFlight
+			}
+
+			return r, nil
+		}
+
+		if !atomic {
+			return nil, storage.Set(key, lock, timeout)
+		}
+
+		swapped, err := swapper.CompareAndSwap(key, nil, lock, timeout)
+		if err != nil || swapped {
+			return nil, err
+		}
+	}
+
+	return nil, ErrKeyInFlight
+}
+
+// KeyByCaller returns a key generator which scopes the idempotency keys to the caller, the callers are identified by
+// the hash of their Authorization header or by their ip when they send none.
+func KeyByCaller() func(c *fiber.Ctx) string {
+	return func(c *fiber.Ctx) string {
+		if credential := c.Request().Header.Peek(fiber.HeaderAuthorization); len(credential) > 0 {
+			h := sha256.Sum256(credential)
+
+			return "credential:" + hex.EncodeToString(h[:])
+		}
+
+		return "ip:" + c.IP()
+	}
+}
+
+// release removes the lock of the key, it returns the error of the request.
+func release(storage database.IKeyValue, key string, err error) error {
+	if e := storage.Delete(key); e != nil && err == nil {
+		return e
+	}
+
+	return err
+}
+
+// response returns the record of the response of the request.
+func response(c *fiber.Ctx, fingerprint string) *record {
+	r := &record{
+		Fingerprint: fingerprint,
+		Status:      c.Response().StatusCode(),
+		Headers:     [][2]string{},
+		Body:        append([]byte{}, c.Response().Body()...),
+	}
+
+	c.Response().Header.VisitAll(func(k, v []byte) {
+		if name := string(k); !skipped(name) {
+			r.Headers = append(r.Headers, [2]string{name, string(v)})
+		}
+	})
+
+	return r
+}
+
+// write replays the stored response.
+func (r *record) write(c *fiber.Ctx) {
+	c.Status(r.Status)
+	for _, header := range r.Headers {
+		c.Response().Header.Set(header[0], header[1])
+	}
+
+	c.Set(HeaderIdempotentReplayed, "true")
+	c.Response().SetBodyRaw(r.Body)
+}
+
+// fingerprint returns the hash of the payload of the request.
+func fingerprint(c *fiber.Ctx) string {
+	h := sha256.New()
+	_, _ = h.Write([]byte(c.Get(fiber.HeaderContentType) + "\n"))
+	_, _ = h.Write(c.Body())
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// get unmarshals the stored value of the key, it returns false when the key does not exist.
+func get(storage database.IKeyValue, key string, value any) (bool, error) {
+	b, err := storage.Get(key)
+	if err != nil || len(b) == 0 {
+		return false, err
+	}
+
+	return true, json.Unmarshal(b, value)
+}
+
+// set marshals and stores the value of the key.
+func set(storage database.IKeyValue, key string, value any, ttl time.Duration) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return storage.Set(key, b, ttl)
+}
+
+// skipped returns true when the response header is not replayed, the names are compared case-insensitively as the
+// headers are normalized.
+func skipped(name string) bool {
+	return slices.ContainsFunc(skippedHeaders, func(header string) bool {
+		return strings.EqualFold(header, name)
+	})
+}
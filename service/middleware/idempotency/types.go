@@ -0,0 +1,70 @@
+package idempotency
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/database"
+)
+
+type (
+	// Config defines the config for middleware.
+	Config struct {
+		// Next defines a function to skip this middleware when returned true.
+		//
+		// Optional. Default: nil
+		Next func(c *fiber.Ctx) bool
+
+		// Methods are the request methods which honor the idempotency key.
+		//
+		// Optional. Default: POST, PATCH
+		Methods []string
+
+		// KeyHeader is the request header of the idempotency key.
+		//
+		// Optional. Default: "Idempotency-Key"
+		KeyHeader string
+
+		// KeyGenerator returns the key which identifies the caller, the idempotency keys of different callers do not
+		// collide.
+		//
+		// Optional. Default: KeyByCaller
+		KeyGenerator func(c *fiber.Ctx) string
+
+		// Retention is the time the first response of a key is replayed.
+		//
+		// Optional. Default: 24 * time.Hour
+		Retention time.Duration
+
+		// RetentionGenerator returns the retention of the response of the request, 0 does not store it.
+		//
+		// Optional. Default: returns Retention
+		RetentionGenerator func(c *fiber.Ctx) time.Duration
+
+		// LockTimeout is the time after which the lock of a request in flight expires,
+		// e.g. when the replica which holds it is gone.
+		//
+		// Optional. Default: 1 * time.Minute
+		LockTimeout time.Duration
+
+		// Storage stores the locks and the responses, the replicas share them through a remote storage which
+		// implements database.IAtomicKeyValue so that a request is not run by two of them.
+		//
+		// Optional. Default: an in-memory storage
+		Storage database.IKeyValue
+
+		// Prefix is prepended to the keys in the Storage.
+		//
+		// Optional. Default: "idempotency:"
+		Prefix string
+	}
+
+	// record represents the lock of a request in flight or its stored response.
+	record struct {
+		Fingerprint string      `json:"fingerprint"`
+		Locked      bool        `json:"locked"`
+		Status      int         `json:"status"`
+		Headers     [][2]string `json:"headers"`
+		Body        []byte      `json:"body"`
+	}
+)
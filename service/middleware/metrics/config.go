@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"github.com/leliuga/cdk/metrics"
+)
+
+// ConfigDefault is the default config
+var (
+	ConfigDefault = Config{
+		Next:    nil,
+		Buckets: metrics.DefaultBuckets,
+	}
+
+	// SizeBuckets are the buckets of the request and response size histograms in bytes.
+	SizeBuckets = []float64{100, 1000, 10000, 100000, 1000000, 10000000}
+)
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	if len(config) < 1 {
+		c := ConfigDefault
+		c.Registry = metrics.NewRegistry()
+
+		return c
+	}
+
+	c := config[0]
+
+	if c.Registry == nil {
+		c.Registry = metrics.NewRegistry()
+	}
+
+	if len(c.Buckets) == 0 {
+		c.Buckets = ConfigDefault.Buckets
+	}
+
+	return c
+}
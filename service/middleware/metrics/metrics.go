@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/leliuga/cdk/service/middleware"
+)
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	cfg := configDefault(config...)
+
+	requests := cfg.Registry.Counter("http_requests_total", "Total number of HTTP requests.", "method", "route", "status")
+	duration := cfg.Registry.Histogram("http_request_duration_seconds", "Duration of HTTP requests in seconds.", cfg.Buckets, "method", "route", "status")
+	requestSize := cfg.Registry.Histogram("http_request_size_bytes", "Size of HTTP requests in bytes.", SizeBuckets, "method", "route", "status")
+	responseSize := cfg.Registry.Histogram("http_response_size_bytes", "Size of HTTP responses in bytes.", SizeBuckets, "method", "route", "status")
+	inflight := cfg.Registry.Gauge("http_requests_in_flight", "Number of HTTP requests being served.")
+
+	return func(c *fiber.Ctx) error {
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		start := time.Now()
+		inflight.Inc()
+		defer inflight.Dec()
+
+		err := c.Next()
+
+		status := middleware.Status(c, err)
+
+		method := utils.CopyString(c.Method())
+		route := c.Route().Path
+		code := strconv.Itoa(status)
+
+		requests.Inc(method, route, code)
+		duration.Observe(time.Since(start).Seconds(), method, route, code)
+		requestSize.Observe(float64(len(c.Request().Header.RawHeaders())+len(c.Request().Body())), method, route, code)
+		responseSize.Observe(float64(len(c.Response().Body())), method, route, code)
+
+		return err
+	}
+}
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/metrics"
+)
+
+type (
+	// Config defines the config for middleware.
+	Config struct {
+		// Next defines a function to skip this middleware when returned true.
+		//
+		// Optional. Default: nil
+		Next func(c *fiber.Ctx) bool
+
+		// Registry is the registry where the request metrics are recorded.
+		//
+		// Optional. Default: a new registry
+		Registry *metrics.Registry
+
+		// Buckets are the buckets of the request duration histogram in seconds.
+		//
+		// Optional. Default: metrics.DefaultBuckets
+		Buckets []float64
+	}
+)
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"strings"
+)
+
+// HasPathPrefix returns true when the path starts with the whole segments of the prefix, e.g. /reports matches
+// /reports and /reports/monthly but not /reportsX. The paths are compared case-insensitively as the routes of the
+// service are matched.
+func HasPathPrefix(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if len(path) < len(prefix) || !strings.EqualFold(path[:len(prefix)], prefix) {
+		return false
+	}
+
+	return len(path) == len(prefix) || path[len(prefix)] == '/'
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"bytes"
+	"strings"
+)
+
+const (
+	AlgorithmInvalid Algorithm = iota //
+	AlgorithmFixedWindow
+	AlgorithmSlidingWindow
+	AlgorithmTokenBucket
+)
+
+var (
+	AlgorithmNames = map[Algorithm]string{
+		AlgorithmFixedWindow:   "fixed_window",
+		AlgorithmSlidingWindow: "sliding_window",
+		AlgorithmTokenBucket:   "token_bucket",
+	}
+)
+
+// String outputs the Algorithm as a string.
+func (a Algorithm) String() string {
+	return AlgorithmNames[a]
+}
+
+// MarshalJSON outputs the Algorithm as a json.
+func (a Algorithm) MarshalJSON() ([]byte, error) {
+	if !a.Validate() {
+		return []byte(`""`), nil
+	}
+
+	return []byte(`"` + a.String() + `"`), nil
+}
+
+// UnmarshalJSON parses the Algorithm from json.
+func (a *Algorithm) UnmarshalJSON(data []byte) error {
+	str := string(bytes.Trim(data, `"`))
+	if algorithm := ParseAlgorithm(str); algorithm.Validate() {
+		*a = algorithm
+	}
+
+	return nil
+}
+
+// Validate returns true if the Algorithm is valid.
+func (a Algorithm) Validate() bool {
+	return a != AlgorithmInvalid
+}
+
+// ParseAlgorithm parses the Algorithm from string.
+func ParseAlgorithm(value string) Algorithm {
+	value = strings.ToLower(value)
+	for k, v := range AlgorithmNames {
+		if v == value {
+			return k
+		}
+	}
+
+	return AlgorithmInvalid
+}
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/storage/memory"
+)
+
+// ConfigDefault is the default config
+var (
+	ConfigDefault = Config{
+		Next:         nil,
+		Algorithm:    AlgorithmFixedWindow,
+		Limit:        100,
+		Window:       1 * time.Minute,
+		KeyGenerator: KeyByIP(),
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusTooManyRequests)
+		},
+		Prefix: "ratelimit:",
+	}
+)
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	if len(config) < 1 {
+		c := ConfigDefault
+		c.Storage = memory.New()
+
+		return c
+	}
+
+	c := config[0]
+
+	if !c.Algorithm.Validate() {
+		c.Algorithm = ConfigDefault.Algorithm
+	}
+
+	if c.Limit <= 0 {
+		c.Limit = ConfigDefault.Limit
+	}
+
+	if c.Window <= 0 {
+		c.Window = ConfigDefault.Window
+	}
+
+	if c.KeyGenerator == nil {
+		c.KeyGenerator = ConfigDefault.KeyGenerator
+	}
+
+	if c.LimitReached == nil {
+		c.LimitReached = ConfigDefault.LimitReached
+	}
+
+	if c.Storage == nil {
+		c.Storage = memory.New()
+	}
+
+	if c.Prefix == "" {
+		c.Prefix = ConfigDefault.Prefix
+	}
+
+	return c
+}
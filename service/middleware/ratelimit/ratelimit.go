@@ -0,0 +1,256 @@
+package ratelimit
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/database"
+)
+
+// Headers of the rate limit
+const (
+	HeaderLimit      = "RateLimit-Limit"
+	HeaderRemaining  = "RateLimit-Remaining"
+	HeaderReset      = "RateLimit-Reset"
+	HeaderPolicy     = "RateLimit-Policy"
+	HeaderRetryAfter = fiber.HeaderRetryAfter
+)
+
+// maxAttempts is the number of times the state of a client is swapped before the request fails with ErrConflict.
+const maxAttempts = 8
+
+var (
+	ErrConflict = errors.New("the rate limit state is updated concurrently")
+)
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	cfg := configDefault(config...)
+	l := &limiter{
+		config: cfg,
+		mutex:  &sync.Mutex{},
+	}
+	policy := strconv.Itoa(cfg.Limit) + ";w=" + strconv.Itoa(int(cfg.Window.Seconds()))
+
+	return func(c *fiber.Ctx) error {
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		result, err := l.take(cfg.KeyGenerator(c), time.Now())
+		if err != nil {
+			return err
+		}
+
+		c.Set(HeaderLimit, strconv.Itoa(result.Limit))
+		c.Set(HeaderRemaining, strconv.Itoa(result.Remaining))
+		c.Set(HeaderReset, strconv.Itoa(seconds(result.Reset)))
+		c.Set(HeaderPolicy, policy)
+
+		if !result.Allowed {
+			c.Set(HeaderRetryAfter, strconv.Itoa(seconds(result.RetryAfter)))
+
+			return cfg.LimitReached(c)
+		}
+
+		return c.Next()
+	}
+}
+
+// KeyByIP returns a key generator which identifies the clients by their ip, the proxy header is used only when the
+// request comes from a trusted proxy.
+func KeyByIP() func(c *fiber.Ctx) string {
+	return func(c *fiber.Ctx) string {
+		return "ip:" + c.IP()
+	}
+}
+
+// KeyByHeader returns a key generator which identifies the clients by the value of the header, the clients which do
+// not send it are identified by their ip.
+func KeyByHeader(name string) func(c *fiber.Ctx) string {
+	return func(c *fiber.Ctx) string {
+		if value := c.Get(name); value != "" {
+			return "header:" + value
+		}
+
+		return "ip:" + c.IP()
+	}
+}
+
+// take counts a request of the client and returns the decision. The state is swapped atomically when the storage
+// implements database.IAtomicKeyValue, otherwise the updates are serialized per instance only and the replicas
+// sharing the storage may lose the requests of each other.
+func (l *limiter) take(key string, now time.Time) (*Result, error) {
+	key = l.config.Prefix + key
+
+	storage, atomic := l.config.Storage.(database.IAtomicKeyValue)
+	if !atomic {
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+	}
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		previous, err := l.config.Storage.Get(key)
+		if err != nil {
+			return nil, err
+		}
+
+		result, next, expiration, err := l.decide(previous, now)
+		if err != nil {
+			return nil, err
+		}
+
+		if !atomic {
+			if err = l.config.Storage.Set(key, next, expiration); err != nil {
+				return nil, err
+			}
+
+			return result, nil
+		}
+
+		swapped, err := storage.CompareAndSwap(key, previous, next, expiration)
+		if err != nil {
+			return nil, err
+		}
+
+		if swapped {
+			return result, nil
+		}
+	}
+
+	return nil, ErrConflict
+}
+
+// decide returns the decision for the stored state of the client along with the next state and its expiration.
+func (l *limiter) decide(previous []byte, now time.Time) (*Result, []byte, time.Duration, error) {
+	s := &state{}
+	if len(previous) > 0 {
+		if err := json.Unmarshal(previous, s); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	var (
+		result     *Result
+		expiration time.Duration
+	)
+
+	switch l.config.Algorithm {
+	case AlgorithmSlidingWindow:
+		result, expiration = l.slidingWindow(s, now)
+	case AlgorithmTokenBucket:
+		result, expiration = l.tokenBucket(s, now)
+	default:
+		result, expiration = l.fixedWindow(s, now)
+	}
+
+	next, err := json.Marshal(s)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return result, next, expiration, nil
+}
+
+// fixedWindow counts the requests in windows aligned to the Window.
+func (l *limiter) fixedWindow(s *state, now time.Time) (*Result, time.Duration) {
+	limit := float64(l.config.Limit)
+	window := l.config.Window.Nanoseconds()
+	start := now.UnixNano() - now.UnixNano()%window
+
+	if s.Start != start {
+		s.Start, s.Count = start, 0
+	}
+
+	allowed := s.Count < limit
+	if allowed {
+		s.Count++
+	}
+
+	reset := time.Duration(start + window - now.UnixNano())
+
+	return &Result{
+		Allowed:    allowed,
+		Limit:      l.config.Limit,
+		Remaining:  int(limit - s.Count),
+		Reset:      reset,
+		RetryAfter: reset,
+	}, reset
+}
+
+// slidingWindow counts the requests in the current window and weights the requests of the previous window by its
+// remaining overlap with the sliding window.
+func (l *limiter) slidingWindow(s *state, now time.Time) (*Result, time.Duration) {
+	limit := float64(l.config.Limit)
+	window := l.config.Window.Nanoseconds()
+	start := now.UnixNano() - now.UnixNano()%window
+
+	if s.Start != start {
+		if s.Start == start-window {
+			s.Previous = s.Count
+		} else {
+			s.Previous = 0
+		}
+		s.Start, s.Count = start, 0
+	}
+
+	elapsed := float64(now.UnixNano() - start)
+	estimate := s.Previous*(1-elapsed/float64(window)) + s.Count
+
+	allowed := estimate+1 <= limit
+	if allowed {
+		s.Count++
+		estimate++
+	}
+
+	reset := time.Duration(start + window - now.UnixNano())
+	retryAfter := reset
+	if s.Previous > 0 && s.Count < limit {
+		// The weight of the previous window drops until one more request fits.
+		retryAfter = time.Duration(float64(window)*(1-(limit-1-s.Count)/s.Previous) - elapsed)
+	}
+
+	return &Result{
+		Allowed:    allowed,
+		Limit:      l.config.Limit,
+		Remaining:  int(math.Max(0, math.Floor(limit-estimate))),
+		Reset:      reset,
+		RetryAfter: retryAfter,
+	}, 2 * l.config.Window
+}
+
+// tokenBucket takes a token from a bucket of Limit tokens which is refilled with Limit tokens per Window.
+func (l *limiter) tokenBucket(s *state, now time.Time) (*Result, time.Duration) {
+	limit := float64(l.config.Limit)
+	rate := limit / float64(l.config.Window.Nanoseconds())
+
+	if s.Start == 0 {
+		s.Count = limit
+	} else {
+		s.Count = math.Min(limit, s.Count+float64(now.UnixNano()-s.Start)*rate)
+	}
+	s.Start = now.UnixNano()
+
+	allowed := s.Count >= 1
+	if allowed {
+		s.Count--
+	}
+
+	return &Result{
+		Allowed:    allowed,
+		Limit:      l.config.Limit,
+		Remaining:  int(math.Floor(s.Count)),
+		Reset:      time.Duration((limit - s.Count) / rate),
+		RetryAfter: time.Duration((1 - s.Count) / rate),
+	}, l.config.Window
+}
+
+// seconds returns the duration in whole seconds rounded up.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
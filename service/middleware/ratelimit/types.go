@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/database"
+)
+
+type (
+	// Config defines the config for middleware.
+	Config struct {
+		// Next defines a function to skip this middleware when returned true.
+		//
+		// Optional. Default: nil
+		Next func(c *fiber.Ctx) bool
+
+		// Algorithm is the algorithm which counts the requests.
+		//
+		// Optional. Default: AlgorithmFixedWindow
+		Algorithm Algorithm
+
+		// Limit is the number of the requests which are allowed per Window,
+		// it is the capacity of the bucket for the token bucket algorithm.
+		//
+		// Optional. Default: 100
+		Limit int
+
+		// Window is the period of the Limit, the token bucket is refilled with Limit tokens per Window.
+		//
+		// Optional. Default: 1 * time.Minute
+		Window time.Duration
+
+		// KeyGenerator returns the key which identifies the client.
+		//
+		// Optional. Default: KeyByIP
+		KeyGenerator func(c *fiber.Ctx) string
+
+		// LimitReached is called when the limit is reached.
+		//
+		// Optional. Default: 429 Too Many Requests
+		LimitReached fiber.Handler
+
+		// Storage stores the state of the clients, the replicas share it through a remote storage which implements
+		// database.IAtomicKeyValue so that no request is lost between them.
+		//
+		// Optional. Default: an in-memory storage
+		Storage database.IKeyValue
+
+		// Prefix is prepended to the keys in the Storage.
+		//
+		// Optional. Default: "ratelimit:"
+		Prefix string
+	}
+
+	// Result represents the decision for a request.
+	Result struct {
+		Allowed    bool
+		Limit      int
+		Remaining  int
+		Reset      time.Duration
+		RetryAfter time.Duration
+	}
+
+	// state represents the stored state of a client.
+	state struct {
+		Count    float64 `json:"count"`
+		Previous float64 `json:"previous"`
+		Start    int64   `json:"start"`
+	}
+
+	// limiter decides for the requests of the clients.
+	limiter struct {
+		config Config
+		mutex  *sync.Mutex
+	}
+
+	// Algorithm represents the rate limiting algorithm.
+	Algorithm uint8
+)
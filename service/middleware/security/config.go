@@ -0,0 +1,26 @@
+package security
+
+// ConfigDefault is the default config
+var (
+	ConfigDefault = Config{
+		Next:               nil,
+		FrameOptions:       "SAMEORIGIN",
+		ReferrerPolicy:     "strict-origin-when-cross-origin",
+		ContentTypeNosniff: true,
+	}
+)
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	c := config[0]
+
+	if c.HSTSMaxAge < 0 {
+		c.HSTSMaxAge = ConfigDefault.HSTSMaxAge
+	}
+
+	return c
+}
@@ -0,0 +1,61 @@
+package security
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	cfg := configDefault(config...)
+
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(cfg.HSTSMaxAge)
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if cfg.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+
+	csp := fiber.HeaderContentSecurityPolicy
+	if cfg.ContentSecurityPolicyReportOnly {
+		csp = fiber.HeaderContentSecurityPolicyReportOnly
+	}
+
+	return func(c *fiber.Ctx) error {
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		if cfg.ContentTypeNosniff {
+			c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+		}
+
+		if cfg.FrameOptions != "" {
+			c.Set(fiber.HeaderXFrameOptions, cfg.FrameOptions)
+		}
+
+		if cfg.ReferrerPolicy != "" {
+			c.Set(fiber.HeaderReferrerPolicy, cfg.ReferrerPolicy)
+		}
+
+		if cfg.PermissionsPolicy != "" {
+			c.Set(fiber.HeaderPermissionsPolicy, cfg.PermissionsPolicy)
+		}
+
+		if cfg.ContentSecurityPolicy != "" {
+			c.Set(csp, cfg.ContentSecurityPolicy)
+		}
+
+		// The browsers ignore the header over http, it is sent over https only.
+		if hsts != "" && c.Protocol() == "https" {
+			c.Set(fiber.HeaderStrictTransportSecurity, hsts)
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,61 @@
+package security
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type (
+	// Config defines the config for middleware.
+	Config struct {
+		// Next defines a function to skip this middleware when returned true.
+		//
+		// Optional. Default: nil
+		Next func(c *fiber.Ctx) bool
+
+		// HSTSMaxAge is the max age in seconds of the Strict-Transport-Security header,
+		// it is sent over https only and 0 disables it.
+		//
+		// Optional. Default: 0
+		HSTSMaxAge int
+
+		// HSTSIncludeSubdomains adds the includeSubDomains directive to the Strict-Transport-Security header.
+		//
+		// Optional. Default: false
+		HSTSIncludeSubdomains bool
+
+		// HSTSPreload adds the preload directive to the Strict-Transport-Security header.
+		//
+		// Optional. Default: false
+		HSTSPreload bool
+
+		// ContentSecurityPolicy is the value of the Content-Security-Policy header.
+		//
+		// Optional. Default: ""
+		ContentSecurityPolicy string
+
+		// ContentSecurityPolicyReportOnly sends the policy as Content-Security-Policy-Report-Only.
+		//
+		// Optional. Default: false
+		ContentSecurityPolicyReportOnly bool
+
+		// FrameOptions is the value of the X-Frame-Options header.
+		//
+		// Optional. Default: "SAMEORIGIN"
+		FrameOptions string
+
+		// ReferrerPolicy is the value of the Referrer-Policy header.
+		//
+		// Optional. Default: "strict-origin-when-cross-origin"
+		ReferrerPolicy string
+
+		// PermissionsPolicy is the value of the Permissions-Policy header.
+		//
+		// Optional. Default: ""
+		PermissionsPolicy string
+
+		// ContentTypeNosniff sends the X-Content-Type-Options: nosniff header.
+		//
+		// Optional. Default: true
+		ContentTypeNosniff bool
+	}
+)
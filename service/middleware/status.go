@@ -0,0 +1,44 @@
+// Package middleware provides the helpers shared by the service middlewares.
+package middleware
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/http"
+	"github.com/leliuga/cdk/validation"
+)
+
+// Status returns the status of the response sent for the error returned by the next handlers, it is derived the way
+// the default error handler of the service does. The status of the response is returned when there is no error.
+func Status(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+
+	return StatusOf(err)
+}
+
+// StatusOf returns the status of the error: the status of an http.IError or a fiber.Error, 422 for the validation
+// errors and 500 for the internal validation errors and any other error.
+func StatusOf(err error) int {
+	var (
+		ve validation.Errors
+		ie validation.InternalError
+		he http.IError
+		fe *fiber.Error
+	)
+
+	switch {
+	case errors.As(err, &ie):
+		return fiber.StatusInternalServerError
+	case errors.As(err, &ve):
+		return fiber.StatusUnprocessableEntity
+	case errors.As(err, &he):
+		return int(he.StatusCode())
+	case errors.As(err, &fe):
+		return fe.Code
+	}
+
+	return fiber.StatusInternalServerError
+}
@@ -0,0 +1,26 @@
+package tracing
+
+import (
+	"github.com/leliuga/cdk/tracing"
+)
+
+// ConfigDefault is the default config
+var (
+	ConfigDefault = Config{
+		Next: nil,
+	}
+)
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	c := ConfigDefault
+	if len(config) > 0 {
+		c = config[0]
+	}
+
+	if c.Tracer == nil {
+		c.Tracer = tracing.NewTracer()
+	}
+
+	return c
+}
@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/leliuga/cdk/service/middleware"
+	"github.com/leliuga/cdk/tracing"
+)
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	cfg := configDefault(config...)
+
+	return func(c *fiber.Ctx) error {
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		ctx := tracing.Extract(c.UserContext(), &carrier{c: c})
+		ctx, span := cfg.Tracer.Start(ctx, c.Method()+" "+c.Path(), tracing.SpanKindServer)
+		defer span.End()
+
+		c.SetUserContext(ctx)
+		err := c.Next()
+
+		status := middleware.Status(c, err)
+
+		// The spans are exported after the request, the values backed by the request buffers are copied.
+		span.Name = c.Method() + " " + c.Route().Path
+		span.SetAttribute("http.method", utils.CopyString(c.Method()))
+		span.SetAttribute("http.route", c.Route().Path)
+		span.SetAttribute("http.target", utils.CopyString(c.OriginalURL()))
+		span.SetAttribute("http.status_code", strconv.Itoa(status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetError(err)
+			if err == nil {
+				span.SetError(errors.New(utils.StatusMessage(status)))
+			}
+		}
+
+		return err
+	}
+}
+
+// Get returns the value of the request header.
+func (h *carrier) Get(key string) string {
+	return h.c.Get(key)
+}
+
+// Set sets the value of the response header.
+func (h *carrier) Set(key, value string) {
+	h.c.Set(key, value)
+}
@@ -0,0 +1,26 @@
+package tracing
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/tracing"
+)
+
+type (
+	// Config defines the config for middleware.
+	Config struct {
+		// Next defines a function to skip this middleware when returned true.
+		//
+		// Optional. Default: nil
+		Next func(c *fiber.Ctx) bool
+
+		// Tracer creates the server spans of the requests.
+		//
+		// Optional. Default: a tracer without exporters
+		Tracer *tracing.Tracer
+	}
+
+	// carrier carries the trace context in the request headers.
+	carrier struct {
+		c *fiber.Ctx
+	}
+)
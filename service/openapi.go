@@ -0,0 +1,152 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/leliuga/cdk/http/openapi"
+	"github.com/leliuga/cdk/http/schema"
+	"github.com/leliuga/cdk/service/middleware"
+	"github.com/leliuga/cdk/types"
+)
+
+const (
+	// DefaultOpenAPIVersion is the version of the OpenAPI document when the service is built without a commit.
+	DefaultOpenAPIVersion = "0.0.0"
+)
+
+var (
+	// bodyContentTypes are the content types of the request bodies which are bound, see Bind.
+	bodyContentTypes = []types.ContentType{types.ContentTypeJson, types.ContentTypeYaml, types.ContentTypeMsgPack}
+
+	// undocumentedPaths are the path prefixes of the built-in endpoints which are not documented.
+	undocumentedPaths = []string{DefaultPathMonitoring, DefaultPathDiscovery, DefaultPathOpenAPI}
+)
+
+// Describe attaches the endpoint metadata and the request and response types to the route of the endpoint method and
+// path, the request fields are documented as the parameters and the body of the route, see Bind.
+func (s *Service) Describe(endpoint *schema.Endpoint, request, response any) {
+	s.descriptions.Set(endpoint.Method.String()+" "+endpoint.Path, &Description{
+		Endpoint: endpoint,
+		Request:  request,
+		Response: response,
+	})
+}
+
+// OpenAPI returns the OpenAPI document of the routes of the service, the described routes are documented with their
+// endpoint metadata and the schemas of their request and response types.
+func (s *Service) OpenAPI() *openapi.Document {
+	version := s.Options.BuildInfo.Commit
+	if version == "" {
+		version = DefaultOpenAPIVersion
+	}
+
+	d := openapi.NewDocument(s.Options.Name, version)
+	d.Info.Description = s.Options.Description
+
+	for _, route := range s.App.GetRoutes(true) {
+		if route.Method == fiber.MethodHead || !documented(route.Path) {
+			continue
+		}
+
+		d.AddOperation(route.Method, route.Path, s.operation(d, route))
+	}
+
+	return d
+}
+
+// OpenAPIHandler returns a fiber handler which serves the OpenAPI document of the service, the document is built by
+// the first request once the routes are registered and it is served from then on.
+func (s *Service) OpenAPIHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		d := s.document.Load()
+		if d == nil {
+			s.document.CompareAndSwap(nil, s.OpenAPI())
+			d = s.document.Load()
+		}
+
+		return c.JSON(d)
+	}
+}
+
+// operation returns the operation of the route, the errors are documented as problem details.
+func (s *Service) operation(d *openapi.Document, route fiber.Route) *openapi.Operation {
+	o := &openapi.Operation{
+		Responses: map[string]*openapi.Response{
+			"default": {
+				Description: "The problem details of the error",
+				Content:     map[string]*openapi.MediaType{ContentTypeProblemJson: {Schema: d.Schema(Problem{})}},
+			},
+		},
+	}
+
+	status, response := fiber.StatusOK, any(nil)
+	if description := s.descriptions.Get(route.Method + " " + route.Path); description != nil {
+		e := description.Endpoint
+		o.OperationID = e.Name
+		o.Summary = e.Description
+		o.Description = e.Description
+		if e.Deprecated != "" {
+			o.Deprecated = true
+			o.Description = strings.TrimSpace(o.Description + "\n\nDeprecated: " + e.Deprecated)
+		}
+
+		if e.Documentation != "" {
+			o.ExternalDocs = &openapi.ExternalDocs{URL: e.Documentation}
+		}
+
+		if e.Expect != nil && e.Expect.Status > 0 {
+			status = int(e.Expect.Status)
+		}
+
+		if description.Request != nil {
+			o.Parameters = d.Parameters(description.Request)
+			if route.Method != fiber.MethodGet && route.Method != fiber.MethodDelete {
+				o.RequestBody = &openapi.RequestBody{Required: true, Content: map[string]*openapi.MediaType{}}
+				for _, ct := range bodyContentTypes {
+					o.RequestBody.Content[ct.String()] = &openapi.MediaType{Schema: d.Schema(description.Request)}
+				}
+			}
+		}
+
+		response = description.Response
+	}
+
+	// The path parameters which are not bound into the request are documented as strings.
+	for _, name := range openapi.PathParameters(route.Path) {
+		if !hasParameter(o.Parameters, name) {
+			o.Parameters = append(o.Parameters, &openapi.Parameter{Name: name, In: openapi.InPath, Required: true, Schema: &openapi.Schema{Type: "string"}})
+		}
+	}
+
+	o.Responses[strconv.Itoa(status)] = &openapi.Response{Description: utils.StatusMessage(status)}
+	if response != nil {
+		o.Responses[strconv.Itoa(status)].Content = map[string]*openapi.MediaType{fiber.MIMEApplicationJSON: {Schema: d.Schema(response)}}
+	}
+
+	return o
+}
+
+// documented returns true if the path is not a built-in endpoint.
+func documented(path string) bool {
+	for _, prefix := range undocumentedPaths {
+		if middleware.HasPathPrefix(path, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasParameter returns true if the path parameter is in the parameters.
+func hasParameter(parameters []*openapi.Parameter, name string) bool {
+	for _, p := range parameters {
+		if p.In == openapi.InPath && p.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/http"
+	"github.com/leliuga/cdk/http/openapi"
+	"github.com/leliuga/cdk/http/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPI(t *testing.T) {
+	s := NewService(NewOptions(WithName("billing"), WithDetectRuntime(false)))
+	assert.Nil(t, s.Boot(context.Background()))
+	defer s.Stop(context.Background())
+
+	s.Post("/invoices/:id", BindHandler[bindInvoice](), func(c *fiber.Ctx) error {
+		return c.JSON(Bound[bindInvoice](c))
+	})
+	s.Get("/invoices/:id/lines/:line", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	endpoint := schema.NewEndpoint("create-invoice", http.MethodPost, "/invoices/:id")
+	endpoint.Description = "Create an invoice"
+	endpoint.Deprecated = "Use POST /v2/invoices"
+	endpoint.Expect.Status = http.StatusCreated
+	s.Describe(endpoint, bindInvoice{}, &bindInvoice{})
+
+	res, err := s.Test(httptest.NewRequest(fiber.MethodGet, DefaultPathOpenAPI, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusOK, res.StatusCode)
+
+	body, _ := io.ReadAll(res.Body)
+	d := &openapi.Document{}
+	assert.Nil(t, json.Unmarshal(body, d))
+	assert.Equal(t, openapi.Version, d.OpenAPI)
+	assert.Equal(t, "billing", d.Info.Title)
+	assert.Len(t, d.Paths, 2)
+
+	create := (*d.Paths["/invoices/{id}"])["post"]
+	assert.Equal(t, "create-invoice", create.OperationID)
+	assert.Equal(t, "Create an invoice", create.Summary)
+	assert.Equal(t, "Create an invoice\n\nDeprecated: Use POST /v2/invoices", create.Description)
+	assert.True(t, create.Deprecated)
+	assert.Contains(t, create.Responses, "201")
+	assert.Contains(t, create.Responses, "default")
+	assert.Equal(t, "#/components/schemas/bindInvoice", create.RequestBody.Content["application/json"].Schema.Ref)
+
+	tests := []struct {
+		tag      string
+		name     string
+		in       string
+		required bool
+		kind     string
+	}{
+		{"t0", "page", openapi.InQuery, false, "integer"},
+		{"t1", "size", openapi.InQuery, false, "array"},
+		{"t2", "wait", openapi.InQuery, false, "string"},
+		{"t3", "id", openapi.InPath, true, "string"},
+		{"t4", "X-Tenant", openapi.InHeader, false, "string"},
+	}
+
+	assert.Len(t, create.Parameters, len(tests))
+	for i, test := range tests {
+		assert.Equal(t, test.name, create.Parameters[i].Name, test.tag)
+		assert.Equal(t, test.in, create.Parameters[i].In, test.tag)
+		assert.Equal(t, test.required, create.Parameters[i].Required, test.tag)
+		assert.Equal(t, test.kind, create.Parameters[i].Schema.Type, test.tag)
+	}
+
+	invoice := d.Components.Schemas["bindInvoice"]
+	assert.Equal(t, []string{"customer"}, invoice.Required)
+	assert.Equal(t, 3, *invoice.Properties["customer"].MinLength)
+	assert.Equal(t, 10, *invoice.Properties["customer"].MaxLength)
+	assert.Equal(t, 1.0, invoice.Properties["amount"].Minimum)
+	assert.NotContains(t, invoice.Properties, "id")
+	assert.Contains(t, d.Components.Schemas, "Problem")
+
+	lines := (*d.Paths["/invoices/{id}/lines/{line}"])["get"]
+	assert.Len(t, lines.Parameters, 2)
+	assert.Contains(t, lines.Responses, "200")
+
+	s.Get("/customers", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	res, err = s.Test(httptest.NewRequest(fiber.MethodGet, DefaultPathOpenAPI, nil))
+	assert.Nil(t, err)
+	cached, _ := io.ReadAll(res.Body)
+	assert.Equal(t, body, cached)
+}
@@ -1,24 +1,25 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path"
-	"path/filepath"
 	"strings"
 	"time"
 
-	"github.com/goccy/go-json"
-	"github.com/goccy/go-yaml"
 	"github.com/gofiber/fiber/v2"
-	"github.com/leliuga/cdk/configurator"
 	"github.com/leliuga/cdk/database"
+	"github.com/leliuga/cdk/types"
+	"github.com/leliuga/cdk/validation"
+	"github.com/leliuga/cdk/validation/is"
 )
 
 // Default values for the HTTP server
 const (
 	DefaultName                    = "service"
 	DefaultPort                    = 3000
+	DefaultAdminPort               = 0
 	DefaultNetwork                 = "tcp4"
 	DefaultBodyLimit               = 4 * 1024 * 1024
 	DefaultConcurrency             = 256 * 1024
@@ -26,27 +27,75 @@ const (
 	DefaultWriteTimeout            = 5 * time.Second
 	DefaultIdleTimeout             = 65 * time.Second
 	DefaultShutdownTimeout         = 10 * time.Second
+	DefaultShutdownDelay           = 0 * time.Second
 	DefaultReadBufferSize          = 4 * 1024
 	DefaultWriteBufferSize         = 4 * 1024
 	DefaultEnableTrustedProxyCheck = false
+	DefaultEnvironment             = EnvironmentProduction
 	DefaultCompressedFileSuffix    = ".gz"
 
 	DefaultConfigDirectory = "/etc/leliuga"
 	DefaultConfigFile      = "config.yaml"
+	DefaultReloadInterval  = 10 * time.Second
 )
 
 // Default paths for the service
 const (
-	DefaultPathMonitoring = "/monitoring"
-	DefaultPathDiscovery  = "/discovery"
+	DefaultPathMonitoring          = "/monitoring"
+	DefaultPathMonitoringLiveness  = DefaultPathMonitoring + "/liveness"
+	DefaultPathMonitoringReadiness = DefaultPathMonitoring + "/readiness"
+	DefaultPathMonitoringStartup   = DefaultPathMonitoring + "/startup"
+	DefaultPathMetrics             = DefaultPathMonitoring + "/metrics"
+	DefaultPathDiscovery           = "/discovery"
+	DefaultPathOpenAPI             = "/.well-known/openapi.json"
 )
 
-// NewOptions creates a new options.
+// NewOptions creates a new options from the defaults, the given options and the environment variables.
 func NewOptions(options ...Option) *Options {
+	opts := newOptions(options...)
+	opts.err = opts.loadEnv()
+	opts.environmentDefaults()
+
+	return opts
+}
+
+// NewOptionsFromConfig creates a new options layered in order of precedence from the defaults,
+// the config file, the overlay config file of the environment and the environment variables.
+func NewOptionsFromConfig(cfgName string, options ...Option) (*Options, error) {
+	opts := newOptions(options...)
+	filename := strings.ToLower(path.Join(DefaultConfigDirectory, opts.Name, cfgName))
+
+	if err := opts.loadFile(filename, SourceFile); err != nil {
+		return nil, err
+	}
+
+	if overlay := opts.overlay(); overlay.Validate() {
+		if err := opts.loadFile(overlayFilename(filename, overlay.String()), SourceOverlay); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	if err := opts.loadEnv(); err != nil {
+		return nil, err
+	}
+	opts.environmentDefaults()
+	opts.ConfigFile = filename
+	opts.loader = func() (*Options, error) {
+		return NewOptionsFromConfig(cfgName, options...)
+	}
+
+	return opts, nil
+}
+
+// newOptions creates a new options from the defaults and the given options.
+func newOptions(options ...Option) *Options {
 	opts := Options{
 		Name:                    DefaultName,
 		Port:                    DefaultPort,
+		AdminPort:               DefaultAdminPort,
 		Network:                 DefaultNetwork,
+		SocketMode:              DefaultSocketMode,
+		ClientAuth:              DefaultClientAuth,
 		Domain:                  strings.ToLower(DefaultName + "." + DefaultDomain),
 		BodyLimit:               DefaultBodyLimit,
 		Concurrency:             DefaultConcurrency,
@@ -54,56 +103,75 @@ func NewOptions(options ...Option) *Options {
 		WriteTimeout:            DefaultWriteTimeout,
 		IdleTimeout:             DefaultIdleTimeout,
 		ShutdownTimeout:         DefaultShutdownTimeout,
+		ShutdownDelay:           DefaultShutdownDelay,
 		ReadBufferSize:          DefaultReadBufferSize,
 		WriteBufferSize:         DefaultWriteBufferSize,
 		EnableTrustedProxyCheck: DefaultEnableTrustedProxyCheck,
 		DisableStartupMessage:   false,
+		Environment:             DefaultEnvironment,
 		EnablePrintRoutes:       true,
+		VerboseErrors:           true,
 		TrustedProxies:          []string{},
 		BuildInfo:               NewBuildInfo("", "", ""),
 		Runtime:                 NewRuntime(),
-		ErrorHandler:            fiber.DefaultErrorHandler,
+		DetectRuntime:           DefaultDetectRuntime,
+		RuntimeDetector:         NewRuntimeDetector(),
+		Redaction:               DefaultRedactionPolicy,
 		Kernel:                  NewKernel(),
 		Database:                database.NewOptions(),
+		AccessLog:               NewAccessLog(),
+		Tracing:                 NewTracing(),
+		RateLimit:               NewRateLimit(),
+		Security:                NewSecurity(),
+		Cache:                   NewCache(),
+		Idempotency:             NewIdempotency(),
+		Middlewares:             append([]string{}, DefaultMiddlewares...),
+		ReloadInterval:          DefaultReloadInterval,
+		Provenance:              types.NewMap[Source](),
+		flags:                   types.NewMap[string](),
 	}
 
 	for _, option := range options {
 		option(&opts)
 	}
 
-	configurator.FromEnv(&opts, "")
-
 	return &opts
 }
 
-// NewOptionsFromConfig creates a new options from config.
-func NewOptionsFromConfig(cfgName string, options ...Option) (*Options, error) {
-	opts := NewOptions(
-		options...,
-	)
-
-	filename := strings.ToLower(path.Join(DefaultConfigDirectory, opts.Name, cfgName))
-	ext := filepath.Ext(filename)
-
-	content, err := os.ReadFile(filename)
-	if err != nil {
-		return nil, err
+// Validate makes Options validatable by implementing [validation.Validatable] interface.
+func (o *Options) Validate() error {
+	if o.err != nil {
+		return o.err
 	}
 
-	switch ext {
-	case ".yaml", ".yml":
-		if err = yaml.UnmarshalWithOptions(content, opts, yaml.UseJSONUnmarshaler()); err != nil {
-			return nil, err
-		}
-	case ".json":
-		if err = json.Unmarshal(content, opts); err != nil {
-			return nil, err
-		}
-	default:
-		return nil, fmt.Errorf("unsupported config file extension: %s", ext)
+	var middlewareNames []any
+	for _, name := range RegisteredMiddlewares() {
+		middlewareNames = append(middlewareNames, name)
 	}
 
-	return opts, nil
+	return validation.ValidateStruct(o,
+		validation.Field(&o.Name, validation.Required, validation.Length(1, 63)),
+		validation.Field(&o.Port, validation.Min(int32(0)), validation.Max(int32(65535))),
+		validation.Field(&o.AdminPort, validation.Min(int32(0)), validation.Max(int32(65535)), validation.When(o.AdminPort != 0, validation.NotIn(o.Port))),
+		validation.Field(&o.ListenAddress, validation.Match(ListenAddressRegex).Error(InvalidListenAddress)),
+		validation.Field(&o.SocketMode, validation.Match(SocketModeRegex)),
+		validation.Field(&o.ClientAuth, validation.Required, validation.In(validation.ToAnySliceFromMapKeys(ClientAuthNames)...).Error(fmt.Sprintf("A client auth value must be one of: %s", strings.Join(types.ToMap(ClientAuthNames).Values(), ", ")))),
+		validation.Field(&o.ClientCAFile, validation.When(o.ClientAuth != ClientAuthNone, validation.Required.Error("A client CA file is required to verify the client certificates."))),
+		validation.Field(&o.Domain, validation.Required, is.Domain),
+		validation.Field(&o.BodyLimit, validation.Min(0)),
+		validation.Field(&o.Concurrency, validation.Min(0)),
+		validation.Field(&o.Environment, validation.Required, validation.In(validation.ToAnySliceFromMapKeys(EnvironmentNames)...).Error(fmt.Sprintf("An environment value must be one of: %s", strings.Join(types.ToMap(EnvironmentNames).Values(), ", ")))),
+		validation.Field(&o.Runtime, validation.Required),
+		validation.Field(&o.VerboseErrors, validation.When(o.Environment == EnvironmentProduction, validation.In(false).Error("Verbose errors must be disabled in production."))),
+		validation.Field(&o.SelfSigned, validation.When(o.Environment == EnvironmentProduction, validation.In(false).Error("A self-signed certificate must not be used in production."))),
+		validation.Field(&o.AccessLog),
+		validation.Field(&o.Tracing),
+		validation.Field(&o.RateLimit),
+		validation.Field(&o.Security),
+		validation.Field(&o.Cache),
+		validation.Field(&o.Idempotency),
+		validation.Field(&o.Middlewares, validation.Each(validation.In(middlewareNames...).Error(fmt.Sprintf("A middleware must be one of: %s", strings.Join(RegisteredMiddlewares(), ", "))))),
+	)
 }
 
 // WithName sets the name for the service.
@@ -111,6 +179,8 @@ func WithName(value string) Option {
 	return func(o *Options) {
 		o.Name = value
 		o.Domain = strings.ToLower(value + "." + DefaultDomain)
+		o.record("name", SourceCode)
+		o.record("domain", SourceCode)
 	}
 }
 
@@ -118,6 +188,15 @@ func WithName(value string) Option {
 func WithDescription(value string) Option {
 	return func(o *Options) {
 		o.Description = value
+		o.record("description", SourceCode)
+	}
+}
+
+// WithEnvironment sets the environment for the service.
+func WithEnvironment(value Environment) Option {
+	return func(o *Options) {
+		o.Environment = value
+		o.record("environment", SourceCode)
 	}
 }
 
@@ -125,6 +204,15 @@ func WithDescription(value string) Option {
 func WithPort(value int32) Option {
 	return func(o *Options) {
 		o.Port = value
+		o.record("port", SourceCode)
+	}
+}
+
+// WithAdminPort sets the port of the admin listener for the service, it is disabled when zero.
+func WithAdminPort(value int32) Option {
+	return func(o *Options) {
+		o.AdminPort = value
+		o.record("admin_port", SourceCode)
 	}
 }
 
@@ -132,6 +220,23 @@ func WithPort(value int32) Option {
 func WithNetwork(value string) Option {
 	return func(o *Options) {
 		o.Network = value
+		o.record("network", SourceCode)
+	}
+}
+
+// WithListenAddress sets the listen address for the service, e.g. unix:///run/service.sock, fd:// or fd://3.
+func WithListenAddress(value string) Option {
+	return func(o *Options) {
+		o.ListenAddress = value
+		o.record("listen_address", SourceCode)
+	}
+}
+
+// WithSocketMode sets the octal file mode of the unix socket for the service, e.g. 0660.
+func WithSocketMode(value string) Option {
+	return func(o *Options) {
+		o.SocketMode = value
+		o.record("socket_mode", SourceCode)
 	}
 }
 
@@ -139,6 +244,7 @@ func WithNetwork(value string) Option {
 func WithDomain(value string) Option {
 	return func(o *Options) {
 		o.Domain = strings.ToLower(value)
+		o.record("domain", SourceCode)
 	}
 }
 
@@ -146,6 +252,7 @@ func WithDomain(value string) Option {
 func WithCertificateFile(value string) Option {
 	return func(o *Options) {
 		o.CertificateFile = value
+		o.record("certificate_file", SourceCode)
 	}
 }
 
@@ -153,6 +260,31 @@ func WithCertificateFile(value string) Option {
 func WithCertificateKeyFile(value string) Option {
 	return func(o *Options) {
 		o.CertificateKeyFile = value
+		o.record("certificate_key_file", SourceCode)
+	}
+}
+
+// WithClientCAFile sets the CA bundle which verifies the client certificates for the service.
+func WithClientCAFile(value string) Option {
+	return func(o *Options) {
+		o.ClientCAFile = value
+		o.record("client_ca_file", SourceCode)
+	}
+}
+
+// WithClientAuth sets the client certificate verification for the service.
+func WithClientAuth(value ClientAuth) Option {
+	return func(o *Options) {
+		o.ClientAuth = value
+		o.record("client_auth", SourceCode)
+	}
+}
+
+// WithSelfSigned sets whether the service generates a self-signed certificate when no certificate is configured.
+func WithSelfSigned(value bool) Option {
+	return func(o *Options) {
+		o.SelfSigned = value
+		o.record("self_signed", SourceCode)
 	}
 }
 
@@ -167,6 +299,7 @@ func WithViews(value fiber.Views) Option {
 func WithBodyLimit(value int) Option {
 	return func(o *Options) {
 		o.BodyLimit = value
+		o.record("body_limit", SourceCode)
 	}
 }
 
@@ -174,6 +307,7 @@ func WithBodyLimit(value int) Option {
 func WithConcurrency(value int) Option {
 	return func(o *Options) {
 		o.Concurrency = value
+		o.record("concurrency", SourceCode)
 	}
 }
 
@@ -181,6 +315,7 @@ func WithConcurrency(value int) Option {
 func WithReadTimeout(value time.Duration) Option {
 	return func(o *Options) {
 		o.ReadTimeout = value
+		o.record("read_timeout", SourceCode)
 	}
 }
 
@@ -188,6 +323,7 @@ func WithReadTimeout(value time.Duration) Option {
 func WithWriteTimeout(value time.Duration) Option {
 	return func(o *Options) {
 		o.WriteTimeout = value
+		o.record("write_timeout", SourceCode)
 	}
 }
 
@@ -195,6 +331,7 @@ func WithWriteTimeout(value time.Duration) Option {
 func WithIdleTimeout(value time.Duration) Option {
 	return func(o *Options) {
 		o.IdleTimeout = value
+		o.record("idle_timeout", SourceCode)
 	}
 }
 
@@ -202,6 +339,23 @@ func WithIdleTimeout(value time.Duration) Option {
 func WithShutdownTimeout(value time.Duration) Option {
 	return func(o *Options) {
 		o.ShutdownTimeout = value
+		o.record("shutdown_timeout", SourceCode)
+	}
+}
+
+// WithShutdownDelay sets the delay between failing the readiness and stopping the listener for the service.
+func WithShutdownDelay(value time.Duration) Option {
+	return func(o *Options) {
+		o.ShutdownDelay = value
+		o.record("shutdown_delay", SourceCode)
+	}
+}
+
+// WithReloadInterval sets the interval of the config file change detection for the service, zero disables it.
+func WithReloadInterval(value time.Duration) Option {
+	return func(o *Options) {
+		o.ReloadInterval = value
+		o.record("reload_interval", SourceCode)
 	}
 }
 
@@ -209,6 +363,7 @@ func WithShutdownTimeout(value time.Duration) Option {
 func WithReadBufferSize(value int) Option {
 	return func(o *Options) {
 		o.ReadBufferSize = value
+		o.record("read_buffer_size", SourceCode)
 	}
 }
 
@@ -216,6 +371,7 @@ func WithReadBufferSize(value int) Option {
 func WithWriteBufferSize(value int) Option {
 	return func(o *Options) {
 		o.WriteBufferSize = value
+		o.record("write_buffer_size", SourceCode)
 	}
 }
 
@@ -223,6 +379,7 @@ func WithWriteBufferSize(value int) Option {
 func WithEnableTrustedProxyCheck(value bool) Option {
 	return func(o *Options) {
 		o.EnableTrustedProxyCheck = value
+		o.record("enable_trusted_proxy_check", SourceCode)
 	}
 }
 
@@ -230,6 +387,7 @@ func WithEnableTrustedProxyCheck(value bool) Option {
 func WithTrustedProxies(values []string) Option {
 	return func(o *Options) {
 		o.TrustedProxies = values
+		o.record("trusted_proxies", SourceCode)
 	}
 }
 
@@ -237,13 +395,23 @@ func WithTrustedProxies(values []string) Option {
 func WithDisableStartupMessage(value bool) Option {
 	return func(o *Options) {
 		o.DisableStartupMessage = value
+		o.record("disable_startup_message", SourceCode)
 	}
 }
 
-// WithEnablePrintRoutes sets the enable print routes for the service.
+// WithEnablePrintRoutes sets the enable print routes for the service, it overrides the environment default.
 func WithEnablePrintRoutes(value bool) Option {
 	return func(o *Options) {
 		o.EnablePrintRoutes = value
+		o.record("enable_print_routes", SourceCode)
+	}
+}
+
+// WithVerboseErrors sets whether the error details are sent to the clients, it overrides the environment default.
+func WithVerboseErrors(value bool) Option {
+	return func(o *Options) {
+		o.VerboseErrors = value
+		o.record("verbose_errors", SourceCode)
 	}
 }
 
@@ -251,6 +419,7 @@ func WithEnablePrintRoutes(value bool) Option {
 func WithBuildInfo(repository, commit, when string) Option {
 	return func(o *Options) {
 		o.BuildInfo = NewBuildInfo(repository, commit, when)
+		o.record("build_info", SourceCode)
 	}
 }
 
@@ -258,6 +427,22 @@ func WithBuildInfo(repository, commit, when string) Option {
 func WithRuntime(value *Runtime) Option {
 	return func(o *Options) {
 		o.Runtime = value
+		o.record("runtime", SourceCode)
+	}
+}
+
+// WithDetectRuntime sets whether the runtime is detected from the execution environment on start, it is opt-in.
+func WithDetectRuntime(value bool) Option {
+	return func(o *Options) {
+		o.DetectRuntime = value
+		o.record("detect_runtime", SourceCode)
+	}
+}
+
+// WithRuntimeDetector sets the runtime detector for the service.
+func WithRuntimeDetector(value *RuntimeDetector) Option {
+	return func(o *Options) {
+		o.RuntimeDetector = value
 	}
 }
 
@@ -265,6 +450,63 @@ func WithRuntime(value *Runtime) Option {
 func WithDatabase(value *database.Options) Option {
 	return func(o *Options) {
 		o.Database = value
+		o.record("database", SourceCode)
+	}
+}
+
+// WithAccessLog sets the access log for the service.
+func WithAccessLog(value *AccessLog) Option {
+	return func(o *Options) {
+		o.AccessLog = value
+		o.record("access_log", SourceCode)
+	}
+}
+
+// WithRateLimit sets the rate limit for the service.
+func WithRateLimit(value *RateLimit) Option {
+	return func(o *Options) {
+		o.RateLimit = value
+		o.record("rate_limit", SourceCode)
+	}
+}
+
+// WithSecurity sets the security headers and the CORS policy for the service.
+func WithSecurity(value *Security) Option {
+	return func(o *Options) {
+		o.Security = value
+		o.record("security", SourceCode)
+	}
+}
+
+// WithCache sets the response cache for the service.
+func WithCache(value *Cache) Option {
+	return func(o *Options) {
+		o.Cache = value
+		o.record("cache", SourceCode)
+	}
+}
+
+// WithIdempotency sets the replay of the mutating requests for the service.
+func WithIdempotency(value *Idempotency) Option {
+	return func(o *Options) {
+		o.Idempotency = value
+		o.record("idempotency", SourceCode)
+	}
+}
+
+// WithMiddlewares sets the names of the middlewares in order for the service.
+func WithMiddlewares(values ...string) Option {
+	return func(o *Options) {
+		o.Middlewares = values
+		o.record("middlewares", SourceCode)
+	}
+}
+
+// WithTracing sets the tracing for the service.
+func WithTracing(value *Tracing) Option {
+	return func(o *Options) {
+		o.Tracing = value
+		o.record("tracing", SourceCode)
 	}
 }
 
@@ -275,6 +517,13 @@ func WithErrorHandler(value func(*fiber.Ctx, error) error) Option {
 	}
 }
 
+// WithRedactionPolicy sets the redaction policy for the service discovery.
+func WithRedactionPolicy(value RedactionPolicy) Option {
+	return func(o *Options) {
+		o.Redaction = value
+	}
+}
+
 // WithKernel sets the kernel for the service.
 func WithKernel(value IKernel) Option {
 	return func(o *Options) {
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+)
+
+const (
+	ProbeInvalid Probe = iota //
+	ProbeLiveness
+	ProbeReadiness
+	ProbeStartup
+)
+
+var (
+	ProbeNames = map[Probe]string{
+		ProbeLiveness:  "liveness",
+		ProbeReadiness: "readiness",
+		ProbeStartup:   "startup",
+	}
+)
+
+// String outputs the Probe as a string.
+func (p Probe) String() string {
+	return ProbeNames[p]
+}
+
+// MarshalJSON outputs the Probe as a json.
+func (p Probe) MarshalJSON() ([]byte, error) {
+	if !p.Validate() {
+		return []byte(`""`), nil
+	}
+
+	return []byte(`"` + p.String() + `"`), nil
+}
+
+// UnmarshalJSON parses the Probe from json.
+func (p *Probe) UnmarshalJSON(data []byte) error {
+	str := string(bytes.Trim(data, `"`))
+	if probe := ParseProbe(str); probe.Validate() {
+		*p = probe
+	}
+
+	return nil
+}
+
+// Validate returns true if the Probe is valid.
+func (p Probe) Validate() bool {
+	return p != ProbeInvalid
+}
+
+// ParseProbe parses the Probe from string.
+func ParseProbe(value string) Probe {
+	value = strings.ToLower(value)
+	for k, v := range ProbeNames {
+		if v == value {
+			return k
+		}
+	}
+
+	return ProbeInvalid
+}
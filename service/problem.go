@@ -0,0 +1,169 @@
+package service
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"html/template"
+	"sort"
+
+	"github.com/flosch/pongo2/v6"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/leliuga/cdk/http"
+	"github.com/leliuga/cdk/render/html"
+	"github.com/leliuga/cdk/service/middleware"
+	"github.com/leliuga/cdk/validation"
+)
+
+// Content types and defaults of the problem details, see RFC 7807
+const (
+	ContentTypeProblemJson = "application/problem+json"
+	ContentTypeProblemXml  = "application/problem+xml"
+	ProblemNamespace       = "urn:ietf:rfc:7807"
+	DefaultProblemType     = "about:blank"
+	DefaultErrorPage       = html.PagesPrefix + "error"
+)
+
+var (
+	// problemTemplate renders the problem when the service has no views or the error page cannot be rendered.
+	problemTemplate = template.Must(template.New("problem").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>{{.Status}} {{.Title}}</title></head>
+<body>
+<h1>{{.Status}} {{.Title}}</h1>
+{{if .Detail}}<p>{{.Detail}}</p>{{end}}
+{{if .Fields}}<ul>{{range .Fields}}<li><strong>{{.Name}}</strong> {{.Message}}</li>{{end}}</ul>{{end}}
+{{if .RequestID}}<p><small>Request ID: {{.RequestID}}</small></p>{{end}}
+</body>
+</html>`))
+)
+
+// NewProblem creates a new Problem of the error of the request. The HTTP errors keep their status code, the validation
+// errors are unprocessable and the others are internal errors whose detail is only sent when verbose is true.
+func NewProblem(c *fiber.Ctx, err error, verbose bool) *Problem {
+	p := &Problem{
+		Type:      DefaultProblemType,
+		Status:    middleware.StatusOf(err),
+		Instance:  c.OriginalURL(),
+		RequestID: c.GetRespHeader(fiber.HeaderXRequestID),
+	}
+
+	var (
+		be *BindError
+		ve validation.Errors
+		ie validation.InternalError
+		he http.IError
+		fe *fiber.Error
+	)
+
+	switch {
+	case errors.As(err, &be):
+		p.Detail, p.Errors = be.Message, be.Errors
+	case errors.As(err, &ie):
+		// The internal errors of the validation rules are never sent to the clients.
+	case errors.As(err, &ve):
+		p.Detail, p.Errors = "The request is invalid", ve
+	case errors.As(err, &he):
+		p.Detail = he.Error()
+	case errors.As(err, &fe):
+		p.Detail = fe.Message
+	case verbose:
+		p.Detail = err.Error()
+	}
+
+	p.Title = utils.StatusMessage(p.Status)
+	if p.Detail == p.Title {
+		p.Detail = ""
+	}
+
+	return p
+}
+
+// Fields returns the invalid fields of the problem sorted by their names, the names of the nested fields are joined
+// by dots.
+func (p *Problem) Fields() []*ProblemField {
+	var fields []*ProblemField
+	flattenErrors("", p.Errors, &fields)
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Name < fields[j].Name
+	})
+
+	return fields
+}
+
+// MarshalXML outputs the Problem as a xml, the errors are listed as fields.
+func (p *Problem) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	type problem Problem
+
+	return e.EncodeElement(struct {
+		*problem
+		Fields []*ProblemField `xml:"errors>error,omitempty"`
+	}{(*problem)(p), p.Fields()}, xml.StartElement{Name: xml.Name{Space: ProblemNamespace, Local: "problem"}})
+}
+
+// problemHandler returns the error handler which sends the problem details, the format is negotiated between
+// problem+json, problem+xml and an html page.
+func problemHandler(options *Options) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		p := NewProblem(c, err, options.VerboseErrors)
+		c.Status(p.Status)
+
+		switch c.Accepts(ContentTypeProblemJson, fiber.MIMEApplicationJSON, ContentTypeProblemXml, fiber.MIMEApplicationXML, fiber.MIMETextXML, fiber.MIMETextHTML) {
+		case ContentTypeProblemXml, fiber.MIMEApplicationXML, fiber.MIMETextXML:
+			b, err := xml.Marshal(p)
+			if err != nil {
+				return err
+			}
+
+			c.Set(fiber.HeaderContentType, ContentTypeProblemXml)
+
+			return c.Send(append([]byte(xml.Header), b...))
+		case fiber.MIMETextHTML:
+			return p.render(c, options.Views)
+		default:
+			if err := c.JSON(p); err != nil {
+				return err
+			}
+
+			c.Set(fiber.HeaderContentType, ContentTypeProblemJson)
+
+			return nil
+		}
+	}
+}
+
+// render sends the problem as the error page of the views, the built-in page is sent without views.
+func (p *Problem) render(c *fiber.Ctx, views fiber.Views) error {
+	if views != nil {
+		if err := c.Render(DefaultErrorPage, pongo2.Context{"problem": p}); err == nil {
+			return nil
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := problemTemplate.Execute(&buffer, p); err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+
+	return c.Send(buffer.Bytes())
+}
+
+// flattenErrors appends the errors to the fields, the nested errors are prefixed by the name of their parent.
+func flattenErrors(prefix string, errs validation.Errors, fields *[]*ProblemField) {
+	for name, err := range errs {
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		if nested, ok := err.(validation.Errors); ok {
+			flattenErrors(name, nested, fields)
+			continue
+		}
+
+		*fields = append(*fields, &ProblemField{Name: name, Message: err.Error()})
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/http"
+	"github.com/leliuga/cdk/service/middleware/requestid"
+	"github.com/leliuga/cdk/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProblemHandler(t *testing.T) {
+	errs := []error{
+		http.NewError(http.StatusNotFound, "The invoice is not found"),
+		validation.Errors{"customer": validation.ErrRequired, "lines": validation.Errors{"0": validation.ErrNilOrNotEmpty}},
+		validation.NewInternalError(errors.New("the database is down")),
+		errors.New("the database is down"),
+		fiber.ErrForbidden,
+	}
+
+	request := func(verbose bool, index int, accept string) (int, string, string) {
+		app := fiber.New(fiber.Config{ErrorHandler: errorHandler(NewOptions(WithVerboseErrors(verbose)))})
+		app.Use(requestid.New(requestid.Config{Generator: func() string { return "r1" }}))
+		app.Get("/", func(*fiber.Ctx) error {
+			return errs[index]
+		})
+
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(fiber.HeaderAccept, accept)
+
+		res, err := app.Test(req)
+		assert.Nil(t, err)
+		body, _ := io.ReadAll(res.Body)
+
+		return res.StatusCode, res.Header.Get(fiber.HeaderContentType), string(body)
+	}
+
+	tests := []struct {
+		tag         string
+		verbose     bool
+		index       int
+		accept      string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"t0", false, 0, "", fiber.StatusNotFound, ContentTypeProblemJson, `{"type":"about:blank","title":"Not Found","status":404,"detail":"The invoice is not found","instance":"/","request_id":"r1"}`},
+		{"t1", false, 1, fiber.MIMEApplicationJSON, fiber.StatusUnprocessableEntity, ContentTypeProblemJson, `"errors":{"customer":"cannot be blank","lines":{"0":"cannot be blank"}}`},
+		{"t2", true, 2, fiber.MIMEApplicationJSON, fiber.StatusInternalServerError, ContentTypeProblemJson, `{"type":"about:blank","title":"Internal Server Error","status":500,"instance":"/","request_id":"r1"}`},
+		{"t3", false, 3, fiber.MIMEApplicationJSON, fiber.StatusInternalServerError, ContentTypeProblemJson, `{"type":"about:blank","title":"Internal Server Error","status":500,"instance":"/","request_id":"r1"}`},
+		{"t4", true, 3, fiber.MIMEApplicationJSON, fiber.StatusInternalServerError, ContentTypeProblemJson, `"detail":"the database is down"`},
+		{"t5", false, 4, fiber.MIMEApplicationJSON, fiber.StatusForbidden, ContentTypeProblemJson, `"title":"Forbidden","status":403,`},
+		{"t6", false, 1, ContentTypeProblemXml, fiber.StatusUnprocessableEntity, ContentTypeProblemXml, `<problem xmlns="urn:ietf:rfc:7807"><type>about:blank</type><title>Unprocessable Entity</title><status>422</status><detail>The request is invalid</detail><instance>/</instance><request_id>r1</request_id><errors><error name="customer">cannot be blank</error><error name="lines.0">cannot be blank</error></errors></problem>`},
+		{"t7", false, 0, "text/html,application/xhtml+xml", fiber.StatusNotFound, fiber.MIMETextHTMLCharsetUTF8, `<h1>404 Not Found</h1>`},
+	}
+
+	for _, test := range tests {
+		status, contentType, body := request(test.verbose, test.index, test.accept)
+		assert.Equal(t, test.status, status, test.tag)
+		assert.Equal(t, test.contentType, contentType, test.tag)
+		assert.Contains(t, body, test.body, test.tag)
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/storage/memory"
+	"github.com/leliuga/cdk/database"
+	"github.com/leliuga/cdk/service/middleware"
+	"github.com/leliuga/cdk/service/middleware/ratelimit"
+	"github.com/leliuga/cdk/types"
+	"github.com/leliuga/cdk/validation"
+)
+
+// Rate limit keys
+const (
+	RateLimitKeyIP     = "ip"
+	RateLimitKeyHeader = "header"
+)
+
+// Default values for the Service rate limit
+const (
+	DefaultRateLimitEnabled   = false
+	DefaultRateLimitAlgorithm = ratelimit.AlgorithmFixedWindow
+	DefaultRateLimitLimit     = 100
+	DefaultRateLimitWindow    = 1 * time.Minute
+	DefaultRateLimitKey       = RateLimitKeyIP
+)
+
+// NewRateLimit creates a new RateLimit.
+func NewRateLimit() *RateLimit {
+	return &RateLimit{
+		Enabled: DefaultRateLimitEnabled,
+		Default: &RateLimitPolicy{
+			Algorithm: DefaultRateLimitAlgorithm,
+			Limit:     DefaultRateLimitLimit,
+			Window:    DefaultRateLimitWindow,
+			Key:       DefaultRateLimitKey,
+		},
+		Exclude: []string{DefaultPathMonitoring},
+		Routes:  types.NewMap[*RateLimitPolicy](),
+	}
+}
+
+// Handler returns the rate limit middleware, it passes the requests through when the rate limit is disabled. The policy
+// of the route with the longest path prefix of the request applies, see middleware.HasPathPrefix, the others use the
+// default policy.
+func (r *RateLimit) Handler() fiber.Handler {
+	if r == nil || !r.Enabled {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	storage := r.Storage
+	if storage == nil {
+		storage = memory.New()
+	}
+
+	fallback := r.Default
+	if fallback == nil {
+		fallback = &RateLimitPolicy{}
+	}
+
+	handler := r.limiter("default", fallback, storage)
+	handlers := map[string]fiber.Handler{}
+	routes := r.Routes.Keys()
+	for _, route := range routes {
+		handlers[route] = r.limiter(route, r.Routes.Get(route).merge(fallback), storage)
+	}
+
+	// The longest prefixes are matched first.
+	sort.Slice(routes, func(i, j int) bool {
+		return len(routes[i]) > len(routes[j])
+	})
+
+	return func(c *fiber.Ctx) error {
+		for _, prefix := range r.Exclude {
+			if middleware.HasPathPrefix(c.Path(), prefix) {
+				return c.Next()
+			}
+		}
+
+		for _, route := range routes {
+			if middleware.HasPathPrefix(c.Path(), route) {
+				return handlers[route](c)
+			}
+		}
+
+		return handler(c)
+	}
+}
+
+// Validate makes RateLimit validatable by implementing [validation.Validatable] interface.
+func (r *RateLimit) Validate() error {
+	return validation.ValidateStruct(r,
+		validation.Field(&r.Default, validation.When(r.Enabled, validation.Required)),
+		validation.Field(&r.Routes),
+	)
+}
+
+// Validate makes RateLimitPolicy validatable by implementing [validation.Validatable] interface.
+func (p *RateLimitPolicy) Validate() error {
+	return validation.ValidateStruct(p,
+		validation.Field(&p.Algorithm, validation.In(validation.ToAnySliceFromMapKeys(ratelimit.AlgorithmNames)...).Error(fmt.Sprintf("An algorithm value must be one of: %s", strings.Join(types.ToMap(ratelimit.AlgorithmNames).Values(), ", ")))),
+		validation.Field(&p.Limit, validation.Min(0)),
+		validation.Field(&p.Window, validation.Min(time.Duration(0))),
+		validation.Field(&p.Key, validation.In(RateLimitKeyIP, RateLimitKeyHeader)),
+		validation.Field(&p.Header, validation.When(p.Key == RateLimitKeyHeader, validation.Required.Error("A header is required to key the clients by header."))),
+	)
+}
+
+// limiter returns the middleware of the policy, the keys are prefixed by the name of the policy.
+func (r *RateLimit) limiter(name string, p *RateLimitPolicy, storage database.IKeyValue) fiber.Handler {
+	key := r.KeyGenerator
+	if key == nil {
+		key = ratelimit.KeyByIP()
+		if p.Key == RateLimitKeyHeader {
+			key = ratelimit.KeyByHeader(p.Header)
+		}
+	}
+
+	return ratelimit.New(ratelimit.Config{
+		Algorithm:    p.Algorithm,
+		Limit:        p.Limit,
+		Window:       p.Window,
+		KeyGenerator: key,
+		Storage:      storage,
+		Prefix:       ratelimit.ConfigDefault.Prefix + name + ":",
+	})
+}
+
+// merge returns the policy with the empty values taken from the fallback policy.
+func (p *RateLimitPolicy) merge(fallback *RateLimitPolicy) *RateLimitPolicy {
+	merged := *p
+
+	if !merged.Algorithm.Validate() {
+		merged.Algorithm = fallback.Algorithm
+	}
+
+	if merged.Limit == 0 {
+		merged.Limit = fallback.Limit
+	}
+
+	if merged.Window == 0 {
+		merged.Window = fallback.Window
+	}
+
+	if merged.Key == "" {
+		merged.Key, merged.Header = fallback.Key, fallback.Header
+	}
+
+	return &merged
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/storage/memory"
+	"github.com/leliuga/cdk/service/middleware/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	tests := []struct {
+		tag       string
+		algorithm ratelimit.Algorithm
+	}{
+		{"t0", ratelimit.AlgorithmFixedWindow},
+		{"t1", ratelimit.AlgorithmSlidingWindow},
+		{"t2", ratelimit.AlgorithmTokenBucket},
+	}
+
+	for _, test := range tests {
+		rateLimit := NewRateLimit()
+		rateLimit.Enabled = true
+		rateLimit.Default.Algorithm = test.algorithm
+		rateLimit.Default.Limit = 2
+		rateLimit.Default.Window = time.Hour
+		rateLimit.Routes.Set("/reports", &RateLimitPolicy{Limit: 1, Key: RateLimitKeyHeader, Header: "X-API-Key"})
+		assert.Nil(t, rateLimit.Validate(), test.tag)
+
+		app := fiber.New()
+		app.Use(rateLimit.Handler())
+		app.Get("/*", func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusNoContent)
+		})
+
+		for i, expected := range []int{fiber.StatusNoContent, fiber.StatusNoContent, fiber.StatusTooManyRequests} {
+			res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/invoices", nil))
+			assert.Nil(t, err, test.tag)
+			assert.Equal(t, expected, res.StatusCode, test.tag, i)
+			assert.Equal(t, "2", res.Header.Get(ratelimit.HeaderLimit), test.tag)
+		}
+
+		res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/invoices", nil))
+		assert.Nil(t, err, test.tag)
+		assert.Equal(t, "0", res.Header.Get(ratelimit.HeaderRemaining), test.tag)
+		assert.NotEmpty(t, res.Header.Get(ratelimit.HeaderRetryAfter), test.tag)
+		assert.NotEqual(t, "0", res.Header.Get(ratelimit.HeaderRetryAfter), test.tag)
+
+		for _, key := range []string{"a", "b"} {
+			req := httptest.NewRequest(fiber.MethodGet, "/reports/monthly", nil)
+			req.Header.Set("X-API-Key", key)
+			res, err = app.Test(req)
+			assert.Nil(t, err, test.tag)
+			assert.Equal(t, fiber.StatusNoContent, res.StatusCode, test.tag)
+			assert.Equal(t, "1", res.Header.Get(ratelimit.HeaderLimit), test.tag)
+		}
+
+		res, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/reportsX", nil))
+		assert.Nil(t, err, test.tag)
+		assert.Equal(t, "2", res.Header.Get(ratelimit.HeaderLimit), test.tag)
+
+		res, err = app.Test(httptest.NewRequest(fiber.MethodGet, DefaultPathMonitoringLiveness, nil))
+		assert.Nil(t, err, test.tag)
+		assert.Equal(t, fiber.StatusNoContent, res.StatusCode, test.tag)
+		assert.Empty(t, res.Header.Get(ratelimit.HeaderLimit), test.tag)
+	}
+}
+
+func TestRateLimitReplicas(t *testing.T) {
+	storage := &atomicStorage{Storage: memory.New(), mutex: &sync.Mutex{}}
+
+	var apps []*fiber.App
+	for i := 0; i < 2; i++ {
+		rateLimit := NewRateLimit()
+		rateLimit.Enabled = true
+		rateLimit.Default.Limit = 10
+		rateLimit.Default.Window = time.Hour
+		rateLimit.Storage = storage
+
+		app := fiber.New()
+		app.Use(rateLimit.Handler())
+		app.Get("/", func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusNoContent)
+		})
+		apps = append(apps, app)
+	}
+
+	var (
+		allowed int32
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(app *fiber.App) {
+			defer wg.Done()
+
+			res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			if err == nil && res.StatusCode == fiber.StatusNoContent {
+				atomic.AddInt32(&allowed, 1)
+			}
+		}(apps[i%2])
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(10), allowed)
+}
+
+func TestRateLimitValidate(t *testing.T) {
+	rateLimit := NewRateLimit()
+	assert.Nil(t, rateLimit.Validate())
+
+	rateLimit.Routes.Set("/reports", &RateLimitPolicy{Key: RateLimitKeyHeader})
+	assert.NotNil(t, rateLimit.Validate())
+}
+
+func TestRateLimitEnv(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "true")
+	t.Setenv("RATE_LIMIT_DEFAULT_ALGORITHM", "token_bucket")
+
+	opts := NewOptions()
+	assert.True(t, opts.RateLimit.Enabled)
+	assert.Equal(t, ratelimit.AlgorithmTokenBucket, opts.RateLimit.Default.Algorithm)
+}
+
+// atomicStorage is an in-memory storage which swaps the values atomically.
+type atomicStorage struct {
+	*memory.Storage
+	mutex *sync.Mutex
+}
+
+func (s *atomicStorage) CompareAndSwap(key string, old, val []byte, exp time.Duration) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	current, err := s.Get(key)
+	if err != nil || !bytes.Equal(current, old) {
+		return false, err
+	}
+
+	return true, s.Set(key, val, exp)
+}
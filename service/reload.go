@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+var (
+	// ReloadablePaths are the option paths applied by Reload, the middlewares and listeners are built at start and
+	// the changes to their options take effect after a restart.
+	ReloadablePaths = []string{"description", "shutdown_timeout", "shutdown_delay", "database"}
+
+	ErrReloadUnsupported = errors.New("the options are not loaded from a config file")
+)
+
+// Reload re-reads the config file and applies its reloadable values, the running options are kept when it is
+// invalid. Only the values at ReloadablePaths are applied, the other changes are logged and take effect after a
+// restart. The kernel is notified about the applied changes when it implements IReloadable.
+func (s *Service) Reload(ctx context.Context) (ConfigDiff, error) {
+	s.reload.Lock()
+	defer s.reload.Unlock()
+
+	current := s.Current()
+	if current.loader == nil {
+		return nil, ErrReloadUnsupported
+	}
+
+	next, err := current.loader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the config %s: %w", current.ConfigFile, err)
+	}
+
+	for path, value := range current.flags {
+		if err = next.Set(path, value, SourceFlag); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = next.Validate(); err != nil {
+		return nil, fmt.Errorf("the config %s is invalid: %w", current.ConfigFile, err)
+	}
+
+	merged := current.merge(next)
+	diff, err := NewConfigDiff(current, merged)
+	if err != nil {
+		return nil, err
+	}
+
+	if pending, err := NewConfigDiff(merged, next); err == nil && len(pending) > 0 {
+		klog.InfoS("the config changes take effect after a restart", "name", current.Name, "file", current.ConfigFile, "changes", pending.Paths())
+	}
+
+	if len(diff) == 0 {
+		return diff, nil
+	}
+
+	s.current.Store(merged)
+
+	if reloadable, ok := s.Kernel.(IReloadable); ok {
+		return diff, reloadable.Reload(ctx, merged, diff)
+	}
+
+	return diff, nil
+}
+
+// Current returns the current options, they reflect the values applied by Reload. The embedded options keep the
+// values the service is started with.
+func (s *Service) Current() *Options {
+	if current := s.current.Load(); current != nil {
+		return current
+	}
+
+	return s.Options
+}
+
+// merge returns a copy of the options with the values at ReloadablePaths taken from the next options, the values set
+// by code or detected at start are kept.
+func (o *Options) merge(next *Options) *Options {
+	merged := *o
+	merged.Description = next.Description
+	merged.ShutdownTimeout = next.ShutdownTimeout
+	merged.ShutdownDelay = next.ShutdownDelay
+	merged.Database = next.Database
+	merged.Provenance = o.Provenance.Clone()
+
+	for path, source := range next.Provenance {
+		if reloadable(path) {
+			merged.Provenance.Set(path, source)
+		}
+	}
+
+	return &merged
+}
+
+// reloadable returns true if the value at the given path is applied by Reload.
+func reloadable(path string) bool {
+	for _, item := range ReloadablePaths {
+		if path == item || strings.HasPrefix(path, item+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// watch reloads the config on SIGHUP or when the config file or its overlay changes, until the context is done.
+func (s *Service) watch(ctx context.Context) {
+	if s.Options.loader == nil {
+		return
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if s.ReloadInterval > 0 {
+		ticker := time.NewTicker(s.ReloadInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	files := s.Options.configFiles()
+	checksum := fileChecksum(files...)
+	for {
+		reason := "signal"
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		case <-tick:
+			current := fileChecksum(files...)
+			if current == checksum {
+				continue
+			}
+			reason = "file change"
+		}
+
+		checksum = fileChecksum(files...)
+		diff, err := s.Reload(ctx)
+		if err != nil {
+			klog.ErrorS(err, "failed to reload the config, the previous config is kept", "name", s.Options.Name, "file", s.ConfigFile, "reason", reason)
+			continue
+		}
+
+		klog.InfoS("the config is reloaded", "name", s.Options.Name, "file", s.ConfigFile, "reason", reason, "changes", diff.Paths())
+	}
+}
+
+// fileChecksum returns the checksum of the content of the files together, a file which cannot be read counts as
+// empty.
+func fileChecksum(filenames ...string) string {
+	h := sha256.New()
+	for _, filename := range filenames {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			content = nil
+		}
+
+		_, _ = fmt.Fprintf(h, "%s\n%x\n", filename, sha256.Sum256(content))
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type reloadableComponent struct {
+	diff ConfigDiff
+}
+
+func (c *reloadableComponent) Boot(context.Context, *Service) error { return nil }
+func (c *reloadableComponent) Shutdown(context.Context) error       { return nil }
+func (c *reloadableComponent) Reload(_ context.Context, _ *Options, diff ConfigDiff) error {
+	c.diff = diff
+
+	return nil
+}
+
+func TestNewConfigDiff(t *testing.T) {
+	previous := NewOptions()
+	next := NewOptions(WithPort(8080))
+	next.Runtime.Region = "eu-central-1"
+
+	diff, err := NewConfigDiff(previous, next)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"port", "runtime.region"}, diff.Paths())
+	assert.Equal(t, float64(DefaultPort), diff[0].Previous)
+	assert.Equal(t, float64(8080), diff[0].Next)
+	assert.True(t, diff.Has("runtime"))
+	assert.False(t, diff.Has("domain"))
+}
+
+func TestServiceReload(t *testing.T) {
+	ctx := context.Background()
+	component := &reloadableComponent{}
+	s := NewService(NewOptions())
+	assert.Nil(t, s.Kernel.(IComponentKernel).Register("component", component))
+	assert.Nil(t, s.Kernel.(IComponentKernel).BootContext(ctx, s))
+
+	_, err := s.Reload(ctx)
+	assert.ErrorIs(t, err, ErrReloadUnsupported)
+
+	s.Options.loader = func() (*Options, error) {
+		return NewOptions(WithDomain("")), nil
+	}
+	_, err = s.Reload(ctx)
+	assert.NotNil(t, err)
+	assert.Equal(t, "service.leliuga.com", s.Current().Domain)
+
+	s.Options.loader = func() (*Options, error) {
+		return NewOptions(WithDescription("Billing service"), WithPort(8080), WithKernel(nil)), nil
+	}
+	diff, err := s.Reload(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"description"}, diff.Paths())
+	assert.Equal(t, diff, component.diff)
+	assert.Equal(t, "Billing service", s.Current().Description)
+	assert.Equal(t, int32(DefaultPort), s.Current().Port)
+	assert.Same(t, s.Kernel, s.Current().Kernel)
+	assert.Empty(t, s.Options.Description)
+}
+
+func TestConfigChecksum(t *testing.T) {
+	t.Setenv(EnvEnvironment, "staging")
+
+	dir := t.TempDir()
+	options := NewOptions()
+	options.ConfigFile = filepath.Join(dir, "config.yaml")
+	assert.Equal(t, []string{options.ConfigFile, filepath.Join(dir, "config.staging.yaml")}, options.configFiles())
+
+	assert.Nil(t, os.WriteFile(options.ConfigFile, []byte("name: billing\n"), 0o600))
+	checksum := fileChecksum(options.configFiles()...)
+	assert.Equal(t, checksum, fileChecksum(options.configFiles()...))
+
+	assert.Nil(t, os.WriteFile(options.configFiles()[1], []byte("port: 8080\n"), 0o600))
+	assert.NotEqual(t, checksum, fileChecksum(options.configFiles()...))
+}
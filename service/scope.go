@@ -0,0 +1,62 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+)
+
+const (
+	ScopeInvalid Scope = iota //
+	ScopeSingleton
+	ScopeRequest
+	ScopeTransient
+)
+
+var (
+	ScopeNames = map[Scope]string{
+		ScopeSingleton: "singleton",
+		ScopeRequest:   "request",
+		ScopeTransient: "transient",
+	}
+)
+
+// String outputs the Scope as a string.
+func (s Scope) String() string {
+	return ScopeNames[s]
+}
+
+// MarshalJSON outputs the Scope as a json.
+func (s Scope) MarshalJSON() ([]byte, error) {
+	if !s.Validate() {
+		return []byte(`""`), nil
+	}
+
+	return []byte(`"` + s.String() + `"`), nil
+}
+
+// UnmarshalJSON parses the Scope from json.
+func (s *Scope) UnmarshalJSON(data []byte) error {
+	str := string(bytes.Trim(data, `"`))
+	if scope := ParseScope(str); scope.Validate() {
+		*s = scope
+	}
+
+	return nil
+}
+
+// Validate returns true if the Scope is valid.
+func (s Scope) Validate() bool {
+	return s != ScopeInvalid
+}
+
+// ParseScope parses the Scope from string.
+func ParseScope(value string) Scope {
+	value = strings.ToLower(value)
+	for k, v := range ScopeNames {
+		if v == value {
+			return k
+		}
+	}
+
+	return ScopeInvalid
+}
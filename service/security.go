@@ -0,0 +1,123 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/leliuga/cdk/service/middleware/security"
+	"github.com/leliuga/cdk/validation"
+)
+
+// Default values for the Service security
+const (
+	DefaultSecurityEnabled        = true
+	DefaultSecurityFrameOptions   = "SAMEORIGIN"
+	DefaultSecurityReferrerPolicy = "strict-origin-when-cross-origin"
+	DefaultHSTSMaxAge             = 180 * 24 * time.Hour
+	DefaultCORSEnabled            = false
+	DefaultCORSMaxAge             = 0 * time.Second
+)
+
+var (
+	DefaultCORSAllowMethods = []string{fiber.MethodGet, fiber.MethodPost, fiber.MethodHead, fiber.MethodPut, fiber.MethodDelete, fiber.MethodPatch}
+)
+
+const (
+	InvalidCORSCredentials = "The credentials must not be allowed for any origin."
+	InvalidFrameOptions    = "A frame options value must be one of: DENY, SAMEORIGIN."
+)
+
+// NewSecurity creates a new Security.
+func NewSecurity() *Security {
+	return &Security{
+		Enabled: DefaultSecurityEnabled,
+		CORS: &CORS{
+			Enabled:       DefaultCORSEnabled,
+			AllowOrigins:  []string{},
+			AllowMethods:  DefaultCORSAllowMethods,
+			AllowHeaders:  []string{},
+			ExposeHeaders: []string{},
+			MaxAge:        DefaultCORSMaxAge,
+		},
+		HSTS: &HSTS{
+			MaxAge: DefaultHSTSMaxAge,
+		},
+		FrameOptions:       DefaultSecurityFrameOptions,
+		ReferrerPolicy:     DefaultSecurityReferrerPolicy,
+		ContentTypeNosniff: true,
+	}
+}
+
+// Handler returns the middleware which sets the security headers, it passes the requests through when the security is
+// disabled.
+func (s *Security) Handler() fiber.Handler {
+	if s == nil || !s.Enabled {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	config := security.Config{
+		ContentSecurityPolicy:           s.ContentSecurityPolicy,
+		ContentSecurityPolicyReportOnly: s.ContentSecurityPolicyReportOnly,
+		FrameOptions:                    s.FrameOptions,
+		ReferrerPolicy:                  s.ReferrerPolicy,
+		PermissionsPolicy:               s.PermissionsPolicy,
+		ContentTypeNosniff:              s.ContentTypeNosniff,
+	}
+
+	if s.HSTS != nil {
+		config.HSTSMaxAge = int(s.HSTS.MaxAge.Seconds())
+		config.HSTSIncludeSubdomains = s.HSTS.IncludeSubdomains
+		config.HSTSPreload = s.HSTS.Preload
+	}
+
+	return security.New(config)
+}
+
+// CORSHandler returns the CORS middleware which answers the preflight requests, it passes the requests through when
+// the security or the CORS policy is disabled.
+func (s *Security) CORSHandler() fiber.Handler {
+	if s == nil || !s.Enabled || s.CORS == nil || !s.CORS.Enabled {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return cors.New(cors.Config{
+		AllowOrigins:     strings.Join(s.CORS.AllowOrigins, ","),
+		AllowMethods:     strings.Join(s.CORS.AllowMethods, ","),
+		AllowHeaders:     strings.Join(s.CORS.AllowHeaders, ","),
+		ExposeHeaders:    strings.Join(s.CORS.ExposeHeaders, ","),
+		AllowCredentials: s.CORS.AllowCredentials,
+		MaxAge:           int(s.CORS.MaxAge.Seconds()),
+	})
+}
+
+// Validate makes Security validatable by implementing [validation.Validatable] interface.
+func (s *Security) Validate() error {
+	return validation.ValidateStruct(s,
+		validation.Field(&s.FrameOptions, validation.In("DENY", "SAMEORIGIN").Error(InvalidFrameOptions)),
+		validation.Field(&s.CORS),
+		validation.Field(&s.HSTS),
+	)
+}
+
+// Validate makes CORS validatable by implementing [validation.Validatable] interface.
+func (c *CORS) Validate() error {
+	return validation.ValidateStruct(c,
+		validation.Field(&c.AllowOrigins, validation.When(c.Enabled, validation.Required)),
+		validation.Field(&c.AllowCredentials, validation.When(slices.Contains(c.AllowOrigins, "*"), validation.In(false).Error(InvalidCORSCredentials))),
+		validation.Field(&c.MaxAge, validation.Min(time.Duration(0))),
+	)
+}
+
+// Validate makes HSTS validatable by implementing [validation.Validatable] interface.
+func (h *HSTS) Validate() error {
+	return validation.ValidateStruct(h,
+		validation.Field(&h.MaxAge, validation.Min(time.Duration(0))),
+	)
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurity(t *testing.T) {
+	security := NewSecurity()
+	security.ContentSecurityPolicy = "default-src 'self'"
+	security.CORS.Enabled = true
+	security.CORS.AllowOrigins = []string{"https://leliuga.com"}
+	security.CORS.AllowCredentials = true
+	assert.Nil(t, security.Validate())
+
+	app := fiber.New()
+	app.Use(security.Handler(), security.CORSHandler())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	tests := []struct {
+		tag     string
+		method  string
+		headers map[string]string
+		header  string
+		value   string
+	}{
+		{"t0", fiber.MethodGet, nil, fiber.HeaderXFrameOptions, DefaultSecurityFrameOptions},
+		{"t1", fiber.MethodGet, nil, fiber.HeaderXContentTypeOptions, "nosniff"},
+		{"t2", fiber.MethodGet, nil, fiber.HeaderContentSecurityPolicy, "default-src 'self'"},
+		{"t3", fiber.MethodGet, nil, fiber.HeaderStrictTransportSecurity, ""},
+		{"t4", fiber.MethodGet, map[string]string{fiber.HeaderXForwardedProto: "https"}, fiber.HeaderStrictTransportSecurity, "max-age=15552000"},
+		{"t5", fiber.MethodGet, map[string]string{fiber.HeaderOrigin: "https://leliuga.com"}, fiber.HeaderAccessControlAllowOrigin, "https://leliuga.com"},
+		{"t6", fiber.MethodGet, map[string]string{fiber.HeaderOrigin: "https://example.com"}, fiber.HeaderAccessControlAllowOrigin, ""},
+		{"t7", fiber.MethodOptions, map[string]string{fiber.HeaderOrigin: "https://leliuga.com", fiber.HeaderAccessControlRequestMethod: fiber.MethodPut}, fiber.HeaderAccessControlAllowCredentials, "true"},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, "/", nil)
+		for k, v := range test.headers {
+			req.Header.Set(k, v)
+		}
+
+		res, err := app.Test(req)
+		assert.Nil(t, err, test.tag)
+		assert.Equal(t, test.value, res.Header.Get(test.header), test.tag)
+	}
+
+	security.CORS.AllowOrigins = []string{"*"}
+	assert.NotNil(t, security.Validate())
+}
+
+func TestServiceMiddlewares(t *testing.T) {
+	ctx := context.Background()
+	RegisterMiddleware("powered-by", func(s *Service) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			c.Set(fiber.HeaderXPoweredBy, s.Options.Name)
+
+			return c.Next()
+		}
+	})
+
+	s := NewService(NewOptions(WithDetectRuntime(false), WithMiddlewares(MiddlewareRecover, "powered-by")))
+	assert.Nil(t, s.Validate())
+	assert.Nil(t, s.Boot(ctx))
+	s.Get("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	res, err := s.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultName, res.Header.Get(fiber.HeaderXPoweredBy))
+	assert.Empty(t, res.Header.Get(fiber.HeaderXFrameOptions))
+	assert.Nil(t, s.Stop(ctx))
+
+	s = NewService(NewOptions(WithDetectRuntime(false), WithMiddlewares("unknown")))
+	assert.NotNil(t, s.Validate())
+	assert.NotNil(t, s.Boot(ctx))
+}
@@ -2,18 +2,24 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/goccy/go-json"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/compress"
-	"github.com/gofiber/fiber/v2/middleware/etag"
-	"github.com/gofiber/fiber/v2/middleware/recover"
-	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/gofiber/fiber/v2/middleware/expvar"
+	"github.com/gofiber/fiber/v2/middleware/pprof"
+	"github.com/leliuga/cdk/http/openapi"
+	"github.com/leliuga/cdk/tracing"
+	"github.com/leliuga/cdk/types"
 	"k8s.io/klog/v2"
 )
 
@@ -23,6 +29,21 @@ const (
 	DefaultVendor          = `Leliuga`
 )
 
+var (
+	ErrServiceStarted    = errors.New("the service is already started")
+	ErrServiceNotStarted = errors.New("the service is not started")
+
+	// CreationPaths are the option paths which are only read when the service is created, they are set by the config
+	// files, the environment or the code.
+	CreationPaths = []string{"name", "network", "enable_trusted_proxy_check", "trusted_proxies", "disable_startup_message", "enable_print_routes"}
+)
+
+// Exit codes of the service process
+const (
+	ExitCodeFailure = 1
+	ExitCodeForced  = 130
+)
+
 // NewService creates a new service.
 func NewService(options *Options) *Service {
 	return &Service{
@@ -45,7 +66,7 @@ func NewService(options *Options) *Service {
 			WriteBufferSize:              options.WriteBufferSize,
 			CompressedFileSuffix:         DefaultCompressedFileSuffix,
 			GETOnly:                      false,
-			ErrorHandler:                 options.ErrorHandler,
+			ErrorHandler:                 errorHandler(options),
 			DisableKeepalive:             false,
 			DisableDefaultDate:           false,
 			DisableDefaultContentType:    false,
@@ -59,74 +80,347 @@ func NewService(options *Options) *Service {
 			JSONDecoder:                  json.Unmarshal,
 			Network:                      options.Network,
 			EnableTrustedProxyCheck:      options.EnableTrustedProxyCheck,
+			ProxyHeader:                  proxyHeader(options),
 			TrustedProxies:               options.TrustedProxies,
 			EnableIPValidation:           false,
 			EnablePrintRoutes:            options.EnablePrintRoutes,
 			ColorScheme:                  fiber.DefaultColors,
 			RequestMethods:               fiber.DefaultMethods,
 		}),
+		Health:  NewHealth(healthTimeout(options)),
+		Metrics: newMetrics(),
+		Admin:   newAdmin(options),
+		Tracer:  tracing.NewTracer(tracing.WithService(options.Name), tracing.WithSampleRate(options.Tracing.SampleRate)),
+
+		descriptions: types.NewMap[*Description](),
+		current:      &atomic.Pointer[Options]{},
+		reload:       &sync.Mutex{},
+		document:     &atomic.Pointer[openapi.Document]{},
 	}
 }
 
-// Serve the service
+// healthTimeout returns the timeout per health check, it is the probe timeout of the runtime when one is set.
+func healthTimeout(options *Options) time.Duration {
+	if options.Runtime == nil || options.Runtime.Probe == nil {
+		return DefaultServiceProbeTimeoutSeconds * time.Second
+	}
+
+	return time.Duration(options.Runtime.Probe.TimeoutSeconds) * time.Second
+}
+
+// proxyHeader returns the header of the client IP, it is only read when the trusted proxies are checked.
+func proxyHeader(options *Options) string {
+	if !options.EnableTrustedProxyCheck {
+		return ""
+	}
+
+	return fiber.HeaderXForwardedFor
+}
+
+// errorHandler returns the error handler of the service, the default one sends the problem details of the error and
+// hides the details of the internal errors unless the verbose errors are enabled.
+func errorHandler(options *Options) fiber.ErrorHandler {
+	if options.ErrorHandler != nil {
+		return options.ErrorHandler
+	}
+
+	return problemHandler(options)
+}
+
+// Serve the service until a termination signal is received or the listener fails, a second signal forces the exit.
 func (s *Service) Serve() error {
-	if err := s.start(); err != nil {
+	if err := s.Start(context.Background()); err != nil {
+		klog.ErrorS(err, "failed to start the service", "name", s.Options.Name, "port", s.Port)
 		return err
 	}
 
-	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGKILL)
+	ch := make(chan os.Signal, 2)
+	signal.Notify(ch, syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
+	defer signal.Stop(ch)
 
-	<-ch
+	select {
+	case err := <-s.errs:
+		klog.ErrorS(err, "the service stopped serving", "name", s.Options.Name, "port", s.Port)
 
-	klog.InfoS("the service is shutting down...", "name", s.Options.Name, "port", s.Port)
-	ctx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
-	defer cancel()
+		return errors.Join(err, s.Stop(context.Background()))
+	case sig := <-ch:
+		go func() {
+			forced := <-ch
+			klog.InfoS("the service is forced to exit", "name", s.Options.Name, "signal", forced.String())
+			os.Exit(ExitCodeForced)
+		}()
 
-	if err := s.Kernel.Shutdown(ctx); err != nil {
+		klog.InfoS("the service is shutting down...", "name", s.Options.Name, "port", s.Port, "signal", sig.String())
+	}
+
+	if err := s.Stop(context.Background()); err != nil {
+		klog.ErrorS(err, "failed to shut down the service", "name", s.Options.Name, "port", s.Port)
+		return err
+	}
+
+	klog.InfoS("the service is shut down", "name", s.Options.Name, "port", s.Port)
+
+	return nil
+}
+
+// Start binds the listener, boots the kernel components unless Boot was called and serves in the background,
+// the errors of serving are sent to the Errors channel.
+func (s *Service) Start(ctx context.Context) error {
+	if s.listener != nil {
+		return ErrServiceStarted
+	}
+
+	if err := s.Options.Validate(); err != nil {
+		return err
+	}
+	s.configure()
+
+	config, err := s.TLSConfig()
+	if err != nil {
+		return err
+	}
+
+	listener, err := s.listen(s.address(), config)
+	if err != nil {
 		return err
 	}
 
+	var adminListener net.Listener
+	if s.Admin != nil {
+		if adminListener, err = s.listen(fmt.Sprintf(":%d", s.AdminPort), nil); err != nil {
+			return errors.Join(err, listener.Close())
+		}
+	}
+
+	if err = s.boot(ctx); err != nil {
+		errs := []error{err, listener.Close()}
+		if adminListener != nil {
+			errs = append(errs, adminListener.Close())
+		}
+
+		return errors.Join(errs...)
+	}
+
+	s.listener = listener
+	s.errs = make(chan error, 2)
+
+	go s.serve(s.App, listener)
+	if adminListener != nil {
+		go s.serve(s.Admin, adminListener)
+	}
+
+	return nil
+}
+
+// Stop shuts the service down, it waits for the in-flight requests until the shutdown timeout.
+func (s *Service) Stop(ctx context.Context) error {
+	if !s.booted {
+		return ErrServiceNotStarted
+	}
+
+	return s.shutdown(ctx)
+}
+
+// Errors returns the channel which receives the error when the service fails to serve.
+func (s *Service) Errors() <-chan error {
+	return s.errs
+}
+
+// Addr returns the address the service is bound to, it is nil when the service is not started.
+func (s *Service) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+
+	return s.listener.Addr()
+}
+
+// shutdown the service in order: readiness, listener with the in-flight requests, admin listener and then kernel components.
+func (s *Service) shutdown(ctx context.Context) error {
+	var errs []error
+
+	s.Health.SetReady(false)
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	options := s.Current()
+	if options.ShutdownDelay > 0 {
+		delay := time.NewTimer(options.ShutdownDelay)
+		select {
+		case <-delay.C:
+		case <-ctx.Done():
+			delay.Stop()
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, options.ShutdownTimeout)
+	defer cancel()
+
 	if err := s.ShutdownWithContext(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to drain the in-flight requests: %w", err))
+	}
+
+	if err := s.cleanup(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if s.Admin != nil {
+		if err := s.Admin.ShutdownWithContext(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shut down the admin listener: %w", err))
+		}
+	}
+
+	if err := s.Kernel.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := s.Tracer.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to flush the spans: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Boot the service without binding a listener, the requests are then served by App.Test or by a later Start. The
+// middlewares are registered before the kernel components boot, the routes registered by the components or after Boot
+// are served behind them.
+func (s *Service) Boot(ctx context.Context) error {
+	if s.booted {
+		return ErrServiceStarted
+	}
+
+	if err := s.Options.Validate(); err != nil {
 		return err
 	}
+	s.configure()
+
+	return s.boot(ctx)
+}
+
+// bootKernel boots the kernel, with the given context when it implements IComponentKernel.
+func (s *Service) bootKernel(ctx context.Context) error {
+	if kernel, ok := s.Kernel.(IComponentKernel); ok {
+		return kernel.BootContext(ctx, s)
+	}
+
+	return s.Kernel.Boot(s)
+}
+
+// boot the service unless it is already booted.
+func (s *Service) boot(ctx context.Context) error {
+	if s.booted {
+		return nil
+	}
 
-	<-ctx.Done()
+	if err := s.start(ctx); err != nil {
+		return err
+	}
+	s.booted = true
 
 	return nil
 }
 
-// start the service
-func (s *Service) start() error {
-	if err := s.Kernel.Boot(s); err != nil {
+// start the service: span exporters, middlewares, kernel components and built-in endpoints
+func (s *Service) start(ctx context.Context) error {
+	if s.DetectRuntime && s.RuntimeDetector != nil {
+		s.RuntimeDetector.Detect(ctx).Apply(s.Options)
+	}
+
+	exporters, err := s.Tracing.Exporters()
+	if err != nil {
 		return err
 	}
+	s.Tracer.Exporters = exporters
 
-	s.Use(
-		recover.New(),
-		compress.New(compress.Config{
-			Level: compress.LevelBestSpeed,
-		}),
-		requestid.New(),
-		etag.New(),
-	)
-
-	go func() {
-		klog.InfoS("the service is serving", "name", s.Options.Name, "port", s.Port)
-		address := fmt.Sprintf(":%d", s.Port)
-
-		if s.CertificateFile != "" && s.CertificateKeyFile != "" {
-			if err := s.ListenTLS(address, s.CertificateFile, s.CertificateKeyFile); err != nil {
-				klog.ErrorS(err, "failed to start the service", "name", s.Options.Name, "port", s.Port)
-				os.Exit(1)
-			}
-		} else {
-			if err := s.Listen(address); err != nil {
-				klog.ErrorS(err, "failed to start the service", "name", s.Options.Name, "port", s.Port)
-				os.Exit(1)
-			}
-		}
-	}()
+	handlers, err := s.handlers()
+	if err != nil {
+		return err
+	}
+
+	if len(handlers) > 0 {
+		s.Use(handlers...)
+	}
+
+	if err = s.bootKernel(ctx); err != nil {
+		return err
+	}
+
+	s.mount()
+	s.Health.SetStarted(true)
+	s.Health.SetReady(true)
+
+	var watchCtx context.Context
+	watchCtx, s.cancel = context.WithCancel(context.Background())
+	go s.watch(watchCtx)
 
 	return nil
 }
+
+// configure applies the options which may be set after the service is created, e.g. by the --set flags, to the
+// servers, the tracer, the health checks and the admin app. The options at CreationPaths are only read by NewService,
+// the booted service is not configured again.
+func (s *Service) configure() {
+	if s.booted {
+		return
+	}
+
+	if s.Admin == nil {
+		s.Admin = newAdmin(s.Options)
+	}
+
+	for _, app := range []*fiber.App{s.App, s.Admin} {
+		if app == nil {
+			continue
+		}
+
+		server := app.Server()
+		server.ReadTimeout = s.ReadTimeout
+		server.WriteTimeout = s.WriteTimeout
+		server.IdleTimeout = s.IdleTimeout
+	}
+
+	server := s.App.Server()
+	if s.BodyLimit > 0 {
+		server.MaxRequestBodySize = s.BodyLimit
+	}
+	if s.Concurrency > 0 {
+		server.Concurrency = s.Concurrency
+	}
+	if s.ReadBufferSize > 0 {
+		server.ReadBufferSize = s.ReadBufferSize
+	}
+	if s.WriteBufferSize > 0 {
+		server.WriteBufferSize = s.WriteBufferSize
+	}
+
+	if s.Tracing != nil {
+		s.Tracer.SampleRate = s.Tracing.SampleRate
+	}
+	s.Health.timeout = healthTimeout(s.Options)
+}
+
+// serve the app on the listener, the error is sent to the Errors channel.
+func (s *Service) serve(app *fiber.App, listener net.Listener) {
+	klog.InfoS("the service is serving", "name", s.Options.Name, "address", listener.Addr().String())
+	if err := app.Listener(listener); err != nil {
+		s.errs <- err
+	}
+}
+
+// mount the built-in service endpoints, they are served by the admin app when the admin port is configured.
+func (s *Service) mount() {
+	var router fiber.Router = s.App
+	if s.Admin != nil {
+		router = s.Admin
+		router.Use(pprof.New(), expvar.New())
+	}
+
+	router.Get(DefaultPathMonitoring, s.Health.Handler(ProbeLiveness))
+	router.Get(DefaultPathMonitoringLiveness, s.Health.Handler(ProbeLiveness))
+	router.Get(DefaultPathMonitoringReadiness, s.Health.Handler(ProbeReadiness))
+	router.Get(DefaultPathMonitoringStartup, s.Health.Handler(ProbeStartup))
+	router.Get(DefaultPathMetrics, s.MetricsHandler())
+	router.Get(DefaultPathDiscovery, s.DiscoveryHandler())
+	router.Get(DefaultPathOpenAPI, s.OpenAPIHandler())
+}
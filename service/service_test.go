@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceStartStop(t *testing.T) {
+	ctx := context.Background()
+	s := NewService(NewOptions(WithPort(0), WithDisableStartupMessage(true), WithDetectRuntime(false)))
+	assert.Nil(t, s.Addr())
+	assert.ErrorIs(t, s.Stop(ctx), ErrServiceNotStarted)
+
+	assert.Nil(t, s.Start(ctx))
+	assert.ErrorIs(t, s.Start(ctx), ErrServiceStarted)
+
+	port := s.Addr().(*net.TCPAddr).Port
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d%s", port, DefaultPathMonitoringReadiness))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = resp.Body.Close()
+
+	other := NewService(NewOptions(WithPort(int32(port)), WithDisableStartupMessage(true), WithDetectRuntime(false)))
+	assert.NotNil(t, other.Start(ctx))
+	assert.Nil(t, other.Addr())
+
+	assert.Nil(t, s.Stop(ctx))
+	assert.False(t, s.Health.Ready())
+}
+
+func TestServiceShutdownDelay(t *testing.T) {
+	s := NewService(NewOptions(WithPort(0), WithShutdownDelay(time.Hour), WithDisableStartupMessage(true), WithDetectRuntime(false)))
+	assert.Nil(t, s.Start(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_ = s.Stop(ctx)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestServiceConfigure(t *testing.T) {
+	ctx := context.Background()
+	s := NewService(NewOptions())
+	assert.Nil(t, s.Options.Set("body_limit", "1024", SourceFlag))
+	assert.Nil(t, s.Options.Set("read_timeout", "3s", SourceFlag))
+	assert.Nil(t, s.Options.Set("admin_port", "9090", SourceFlag))
+	assert.Nil(t, s.Options.Set("tracing.sample_rate", "0.5", SourceFlag))
+
+	assert.Nil(t, s.Boot(ctx))
+	defer s.Stop(ctx)
+
+	assert.Equal(t, 1024, s.Server().MaxRequestBodySize)
+	assert.Equal(t, 3*time.Second, s.Server().ReadTimeout)
+	assert.Equal(t, 0.5, s.Tracer.SampleRate)
+	if assert.NotNil(t, s.Admin) {
+		assert.Equal(t, 3*time.Second, s.Admin.Server().ReadTimeout)
+	}
+}
+
+func TestServiceAdmin(t *testing.T) {
+	ctx := context.Background()
+	free, err := net.Listen("tcp4", ":0")
+	assert.Nil(t, err)
+	adminPort := free.Addr().(*net.TCPAddr).Port
+	assert.Nil(t, free.Close())
+
+	s := NewService(NewOptions(WithPort(0), WithAdminPort(int32(adminPort)), WithDisableStartupMessage(true), WithDetectRuntime(false)))
+	assert.Equal(t, int32(adminPort), s.MonitoringPort())
+	assert.Nil(t, s.Start(ctx))
+	defer func() { assert.Nil(t, s.Stop(ctx)) }()
+
+	tests := []struct {
+		tag    string
+		port   int
+		path   string
+		status int
+	}{
+		{"t0", s.Addr().(*net.TCPAddr).Port, DefaultPathMonitoringLiveness, http.StatusNotFound},
+		{"t1", adminPort, DefaultPathMonitoringLiveness, http.StatusOK},
+		{"t2", adminPort, DefaultPathMetrics, http.StatusOK},
+		{"t3", adminPort, "/debug/pprof/", http.StatusOK},
+		{"t4", adminPort, "/debug/vars", http.StatusOK},
+	}
+
+	for _, test := range tests {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d%s", test.port, test.path))
+		assert.Nil(t, err, test.tag)
+		assert.Equal(t, test.status, resp.StatusCode, test.tag)
+		_ = resp.Body.Close()
+	}
+}
+
+func TestServiceUnixSocket(t *testing.T) {
+	ctx := context.Background()
+	socket := filepath.Join(t.TempDir(), "service.sock")
+	s := NewService(NewOptions(WithListenAddress(ListenSchemeUnix+socket), WithDisableStartupMessage(true), WithDetectRuntime(false)))
+	assert.Nil(t, s.Validate())
+	assert.Nil(t, s.Start(ctx))
+
+	info, err := os.Stat(socket)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0660), info.Mode().Perm())
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	resp, err := client.Get("http://service" + DefaultPathMonitoringLiveness)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = resp.Body.Close()
+	client.CloseIdleConnections()
+
+	assert.Nil(t, s.Stop(ctx))
+	_, err = os.Stat(socket)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestInheritedListener(t *testing.T) {
+	_, err := inheritedListener("")
+	assert.ErrorIs(t, err, ErrListenerNotInherited)
+
+	assert.NotNil(t, NewOptions(WithListenAddress("tcp://:80")).Validate())
+	assert.Nil(t, NewOptions(WithListenAddress("fd://http")).Validate())
+}
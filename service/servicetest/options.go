@@ -0,0 +1,69 @@
+package servicetest
+
+import (
+	"time"
+
+	"github.com/leliuga/cdk/http/client"
+	"github.com/leliuga/cdk/service"
+)
+
+// Default values for the service test
+const (
+	DefaultInMemory = false
+	DefaultTimeout  = 5 * time.Second
+)
+
+// NewOptions creates a new service test options.
+func NewOptions(options ...Option) *Options {
+	opts := Options{
+		InMemory:   DefaultInMemory,
+		Timeout:    DefaultTimeout,
+		Components: []*Component{},
+		Client:     []client.Option{},
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &opts
+}
+
+// WithInMemory sets whether the requests are served in memory instead of an ephemeral listener.
+func WithInMemory(value bool) Option {
+	return func(o *Options) {
+		o.InMemory = value
+	}
+}
+
+// WithTimeout sets the timeout of the requests and of the shutdown.
+func WithTimeout(value time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = value
+	}
+}
+
+// WithComponent replaces the registered kernel component with the given name, it is registered when missing.
+func WithComponent(name string, component service.IComponent, dependencies ...string) Option {
+	return func(o *Options) {
+		o.Components = append(o.Components, &Component{
+			Name:         name,
+			Component:    component,
+			Dependencies: dependencies,
+		})
+	}
+}
+
+// WithSetup sets the function which registers the routes, it runs when the last kernel component boots.
+func WithSetup(value func(s *service.Service)) Option {
+	return func(o *Options) {
+		o.Setup = value
+	}
+}
+
+// WithClient sets the options of the client, they are applied after the base uri and the transport.
+func WithClient(values ...client.Option) Option {
+	return func(o *Options) {
+		o.Client = append(o.Client, values...)
+	}
+}
@@ -0,0 +1,71 @@
+package servicetest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/leliuga/cdk/event"
+	"github.com/leliuga/cdk/types"
+)
+
+// NewRecorder creates a new span exporter which captures the exported events.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		events: []*event.Event{},
+		mutex:  &sync.Mutex{},
+	}
+}
+
+// Export captures the event.
+func (r *Recorder) Export(_ context.Context, e *event.Event) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.events = append(r.events, e)
+
+	return nil
+}
+
+// Shutdown does nothing, the captured events are kept.
+func (r *Recorder) Shutdown(context.Context) error {
+	return nil
+}
+
+// Events returns the captured events in export order.
+func (r *Recorder) Events() []*event.Event {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]*event.Event{}, r.events...)
+}
+
+// Find returns the first captured event of the kind which has all the given attributes.
+func (r *Recorder) Find(kind event.Kind, attributes types.Map[string]) *event.Event {
+	for _, e := range r.Events() {
+		if e.Kind != kind {
+			continue
+		}
+
+		matched := true
+		for key, value := range attributes {
+			if e.Attributes.Get(key) != value {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// Reset discards the captured events.
+func (r *Recorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.events = []*event.Event{}
+}
@@ -0,0 +1,201 @@
+package servicetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/leliuga/cdk/event"
+	"github.com/leliuga/cdk/http/client"
+	"github.com/leliuga/cdk/http/schema"
+	"github.com/leliuga/cdk/service"
+	"github.com/leliuga/cdk/tracing"
+	"github.com/leliuga/cdk/types"
+)
+
+const (
+	// InMemoryURL is the base url of the client when the requests are served in memory.
+	InMemoryURL = "http://servicetest"
+
+	// SetupComponent is the name of the kernel component which runs the setup.
+	SetupComponent = "servicetest.setup"
+)
+
+// New boots a service from the options for the duration of the test, it is stopped by t.Cleanup. Only the options
+// left to their defaults are changed: the service listens on an ephemeral port unless it is served in memory, the
+// startup message and the routes are not printed and every span is traced. The spans are captured by the Recorder
+// which replaces the exporters of the tracing. The setup is booted as the last kernel component, so its routes are
+// served behind the middlewares and ahead of the built-in endpoints as the routes of the components are in production.
+func New(t testing.TB, options *service.Options, opts ...Option) *Server {
+	t.Helper()
+
+	o := NewOptions(opts...)
+	if options == nil {
+		options = service.NewOptions()
+	}
+
+	if unset(options, "disable_startup_message", !options.DisableStartupMessage) {
+		options.DisableStartupMessage = true
+	}
+	if unset(options, "enable_print_routes", true) {
+		options.EnablePrintRoutes = false
+	}
+	if options.ListenAddress == "" && unset(options, "port", options.Port == service.DefaultPort) {
+		options.Port = 0
+	}
+	if options.Tracing == nil || unset(options, "tracing", !options.Tracing.Enabled) {
+		options.Tracing = &service.Tracing{
+			Enabled:    true,
+			SampleRate: 1,
+			Exporter:   service.TracingExporterStdout,
+		}
+	}
+
+	s := &Server{
+		Service:  service.NewService(options),
+		Recorder: NewRecorder(),
+		t:        t,
+	}
+
+	kernel, ok := s.Kernel.(service.IComponentKernel)
+	if !ok && (len(o.Components) > 0 || o.Setup != nil) {
+		t.Fatalf("servicetest: the kernel %T does not register components", s.Kernel)
+	}
+
+	for _, c := range o.Components {
+		if err := kernel.Replace(c.Name, c.Component); err != nil {
+			if !errors.Is(err, service.ErrComponentNotFound) {
+				t.Fatalf("servicetest: %s", err)
+			}
+
+			if err = kernel.Register(c.Name, c.Component, c.Dependencies...); err != nil {
+				t.Fatalf("servicetest: %s", err)
+			}
+		}
+	}
+
+	if o.Setup != nil {
+		dependencies, err := kernel.Components()
+		if err != nil {
+			t.Fatalf("servicetest: %s", err)
+		}
+
+		if err = kernel.Register(SetupComponent, setup(o.Setup), dependencies...); err != nil {
+			t.Fatalf("servicetest: %s", err)
+		}
+	}
+
+	if err := s.Boot(context.Background()); err != nil {
+		t.Fatalf("servicetest: failed to boot the service: %s", err)
+	}
+	s.Tracer.Exporters = []tracing.IExporter{s.Recorder}
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), o.Timeout)
+		defer cancel()
+
+		if err := s.Stop(ctx); err != nil {
+			t.Errorf("servicetest: failed to stop the service: %s", err)
+		}
+	})
+
+	clientOptions := []client.Option{client.WithTimeout(o.Timeout)}
+	if o.InMemory {
+		s.URL = InMemoryURL
+		clientOptions = append(clientOptions, client.WithTransport(NewTransport(s.App, o.Timeout)))
+	} else {
+		if err := s.Start(context.Background()); err != nil {
+			t.Fatalf("servicetest: failed to start the service: %s", err)
+		}
+
+		s.URL = s.url()
+	}
+
+	s.Client = client.NewClient(client.NewOptions(append(append(clientOptions, client.WithBaseUri(s.URL)), o.Client...)...))
+
+	return s
+}
+
+// Do sends the request of the endpoint to the service.
+func (s *Server) Do(endpoint *schema.Endpoint) (*client.Response, error) {
+	return s.Client.Do(context.Background(), endpoint)
+}
+
+// AssertExpect sends the request of the endpoint and fails the test when the response does not meet its expectation.
+func (s *Server) AssertExpect(endpoint *schema.Endpoint) *client.Response {
+	s.t.Helper()
+
+	res, err := s.Do(endpoint)
+	if err != nil {
+		s.t.Errorf("servicetest: %s %s: %s", endpoint.Method, endpoint.Path, err)
+
+		return nil
+	}
+
+	s.t.Cleanup(func() {
+		_ = res.Close()
+	})
+
+	return res
+}
+
+// AssertEvent waits for an event of the kind with the given attributes and fails the test when it is not captured
+// within the timeout, the spans are exported after the response is sent.
+func (s *Server) AssertEvent(kind event.Kind, attributes types.Map[string]) *event.Event {
+	s.t.Helper()
+
+	deadline := time.Now().Add(s.Client.Timeout)
+	for {
+		if e := s.Recorder.Find(kind, attributes); e != nil {
+			return e
+		}
+
+		if time.Now().After(deadline) {
+			s.t.Errorf("servicetest: no %s event with the attributes %v, captured %d events", kind, attributes, len(s.Recorder.Events()))
+
+			return nil
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// url returns the base url of the ephemeral listener.
+func (s *Server) url() string {
+	scheme := "http"
+	if config, err := s.TLSConfig(); err == nil && config != nil {
+		scheme = "https"
+	}
+
+	if addr, ok := s.Addr().(*net.TCPAddr); ok {
+		host := addr.IP.String()
+		if addr.IP.IsUnspecified() {
+			host = "127.0.0.1"
+		}
+
+		return fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, fmt.Sprint(addr.Port)))
+	}
+
+	return fmt.Sprintf("%s://%s", scheme, s.Addr().String())
+}
+
+// Boot registers the routes of the setup.
+func (fn setup) Boot(_ context.Context, s *service.Service) error {
+	fn(s)
+
+	return nil
+}
+
+// Shutdown does nothing, the routes are removed with the service.
+func (fn setup) Shutdown(context.Context) error {
+	return nil
+}
+
+// unset returns true when the value at the given path is not set by a source and isDefault reports that it is the
+// default value, the values assigned without a With function have no source.
+func unset(options *service.Options, path string, isDefault bool) bool {
+	return options.SourceOf(path) == service.SourceDefault && isDefault
+}
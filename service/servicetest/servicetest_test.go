@@ -0,0 +1,92 @@
+package servicetest
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/event"
+	"github.com/leliuga/cdk/http"
+	"github.com/leliuga/cdk/http/schema"
+	"github.com/leliuga/cdk/service"
+	"github.com/leliuga/cdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type greeter struct {
+	greeting string
+	booted   bool
+}
+
+func (g *greeter) Boot(context.Context, *service.Service) error { g.booted = true; return nil }
+func (g *greeter) Shutdown(context.Context) error               { return nil }
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		tag      string
+		inMemory bool
+	}{
+		{"t0", false},
+		{"t1", true},
+	}
+
+	for _, test := range tests {
+		options := service.NewOptions()
+		assert.Nil(t, options.Kernel.(service.IComponentKernel).Register("greeter", &greeter{greeting: "hello"}), test.tag)
+
+		replacement := &greeter{greeting: "hi"}
+		s := New(t, options,
+			WithInMemory(test.inMemory),
+			WithComponent("greeter", replacement),
+			WithSetup(func(s *service.Service) {
+				s.Get("/greeting", func(c *fiber.Ctx) error {
+					return c.SendString(s.Kernel.Get("greeter").(*greeter).greeting)
+				})
+			}),
+		)
+		assert.True(t, replacement.booted, test.tag)
+
+		endpoint := schema.NewEndpoint("greeting", http.MethodGet, "/greeting")
+		res := s.AssertExpect(endpoint)
+		if assert.NotNil(t, res, test.tag) {
+			var greeting []byte
+			greeting, _ = io.ReadAll(res.Body())
+			assert.Equal(t, "hi", string(greeting), test.tag)
+		}
+
+		monitoring := schema.NewEndpoint("monitoring", http.MethodGet, service.DefaultPathMonitoringReadiness)
+		assert.NotNil(t, s.AssertExpect(monitoring), test.tag)
+
+		e := s.AssertEvent(event.KindApplicationTrace, types.Map[string]{"name": "GET /greeting"})
+		assert.NotNil(t, e, test.tag)
+	}
+}
+
+func TestNewWithoutOptions(t *testing.T) {
+	s := New(t, nil, WithInMemory(true))
+
+	endpoint := schema.NewEndpoint("missing", http.MethodGet, "/missing")
+	endpoint.Expect.Status = http.StatusNotFound
+	assert.NotNil(t, s.AssertExpect(endpoint))
+	assert.Nil(t, s.Recorder.Find(event.KindApplicationTrace, types.Map[string]{"name": "GET /greeting"}))
+}
+
+func TestNewKeepsOptions(t *testing.T) {
+	options := service.NewOptions(service.WithTracing(&service.Tracing{
+		Enabled:    true,
+		SampleRate: 0.5,
+		Exporter:   service.TracingExporterStdout,
+	}))
+	assert.Nil(t, options.Set("port", "8080", service.SourceFlag))
+
+	New(t, options, WithInMemory(true))
+	assert.Equal(t, int32(8080), options.Port)
+	assert.Equal(t, 0.5, options.Tracing.SampleRate)
+
+	defaults := service.NewOptions()
+	New(t, defaults, WithInMemory(true))
+	assert.Equal(t, int32(0), defaults.Port)
+	assert.Equal(t, 1.0, defaults.Tracing.SampleRate)
+	assert.True(t, defaults.Tracing.Enabled)
+}
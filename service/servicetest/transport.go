@@ -0,0 +1,21 @@
+package servicetest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewTransport creates a new round tripper which serves the requests in memory through App.Test.
+func NewTransport(app *fiber.App, timeout time.Duration) *Transport {
+	return &Transport{
+		app:     app,
+		timeout: timeout,
+	}
+}
+
+// RoundTrip serves the request by the app without a network connection.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.app.Test(req, int(t.timeout.Milliseconds()))
+}
@@ -0,0 +1,59 @@
+// Package servicetest provides helpers to test a service in process.
+package servicetest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/leliuga/cdk/event"
+	"github.com/leliuga/cdk/http/client"
+	"github.com/leliuga/cdk/service"
+)
+
+type (
+	// Server represents a booted service under test.
+	Server struct {
+		*service.Service
+		Client   *client.Client
+		Recorder *Recorder
+		URL      string
+
+		t testing.TB
+	}
+
+	// Recorder is a span exporter which captures the exported events.
+	Recorder struct {
+		events []*event.Event
+		mutex  *sync.Mutex
+	}
+
+	// Transport is a round tripper which serves the requests in memory through App.Test.
+	Transport struct {
+		app     *fiber.App
+		timeout time.Duration
+	}
+
+	// Options represents the service test options.
+	Options struct {
+		InMemory   bool
+		Timeout    time.Duration
+		Components []*Component
+		Setup      func(s *service.Service)
+		Client     []client.Option
+	}
+
+	// Component represents a kernel component which replaces or is registered next to the components of the service.
+	Component struct {
+		Name         string
+		Component    service.IComponent
+		Dependencies []string
+	}
+
+	// setup is the kernel component which registers the routes of the setup when it boots.
+	setup func(s *service.Service)
+
+	// Option represents the service test option.
+	Option func(o *Options)
+)
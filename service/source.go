@@ -0,0 +1,70 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+)
+
+const (
+	SourceInvalid Source = iota //
+	SourceDefault
+	SourceCode
+	SourceDetected
+	SourceFile
+	SourceOverlay
+	SourceEnv
+	SourceFlag
+)
+
+var (
+	SourceNames = map[Source]string{
+		SourceDefault:  "default",
+		SourceCode:     "code",
+		SourceDetected: "detected",
+		SourceFile:     "file",
+		SourceOverlay:  "overlay",
+		SourceEnv:      "env",
+		SourceFlag:     "flag",
+	}
+)
+
+// String outputs the Source as a string.
+func (s Source) String() string {
+	return SourceNames[s]
+}
+
+// MarshalJSON outputs the Source as a json.
+func (s Source) MarshalJSON() ([]byte, error) {
+	if !s.Validate() {
+		return []byte(`""`), nil
+	}
+
+	return []byte(`"` + s.String() + `"`), nil
+}
+
+// UnmarshalJSON parses the Source from json.
+func (s *Source) UnmarshalJSON(data []byte) error {
+	str := string(bytes.Trim(data, `"`))
+	if source := ParseSource(str); source.Validate() {
+		*s = source
+	}
+
+	return nil
+}
+
+// Validate returns true if the Source is valid.
+func (s Source) Validate() bool {
+	return s != SourceInvalid
+}
+
+// ParseSource parses the Source from string.
+func ParseSource(value string) Source {
+	value = strings.ToLower(value)
+	for k, v := range SourceNames {
+		if v == value {
+			return k
+		}
+	}
+
+	return SourceInvalid
+}
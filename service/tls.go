@@ -0,0 +1,219 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"k8s.io/klog/v2"
+)
+
+// Default values for the Service TLS
+const (
+	DefaultClientAuth             = ClientAuthNone
+	DefaultSelfSignedValidity     = 365 * 24 * time.Hour
+	DefaultCertificateReloadCheck = 10 * time.Second
+)
+
+var (
+	ErrClientCANotLoaded    = errors.New("the client CA bundle contains no certificates")
+	ErrClientCARequired     = errors.New("a client CA file is required to verify the client certificates")
+	ErrClientAuthWithoutTLS = errors.New("the client certificates are verified only when a certificate is configured")
+)
+
+// NewCertificateReloader creates a new certificate reloader, the key pair is loaded immediately and the files are
+// checked for changes at most once per interval.
+func NewCertificateReloader(certificateFile, keyFile string, interval time.Duration) (*CertificateReloader, error) {
+	r := &CertificateReloader{
+		certificateFile: certificateFile,
+		keyFile:         keyFile,
+		interval:        interval,
+		mutex:           &sync.Mutex{},
+	}
+
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetCertificate returns the current key pair, it is reloaded when the files changed, on failure the previous
+// key pair is kept.
+func (r *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if time.Since(r.checked) >= r.interval {
+		r.checked = time.Now()
+		if r.changed() {
+			if err := r.reload(); err != nil {
+				klog.ErrorS(err, "failed to reload the certificate", "file", r.certificateFile)
+			}
+		}
+	}
+
+	return r.certificate, nil
+}
+
+// changed returns true if one of the files was modified after the loaded key pair.
+func (r *CertificateReloader) changed() bool {
+	return r.modifiedAt().After(r.modified)
+}
+
+// modifiedAt returns the latest modification time of the files.
+func (r *CertificateReloader) modifiedAt() time.Time {
+	var latest time.Time
+
+	for _, filename := range []string{r.certificateFile, r.keyFile} {
+		if info, err := os.Stat(filename); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest
+}
+
+// reload loads the key pair from the files.
+func (r *CertificateReloader) reload() error {
+	modified := r.modifiedAt()
+
+	certificate, err := tls.LoadX509KeyPair(r.certificateFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load the certificate: %w", err)
+	}
+
+	r.certificate = &certificate
+	r.modified = modified
+	r.checked = time.Now()
+
+	return nil
+}
+
+// TLSConfig returns the TLS config of the service, it is nil when neither a certificate nor a self-signed
+// certificate is configured. The client certificates are verified only against the client CA bundle, it fails when
+// they are requested without a certificate or a client CA file.
+func (o *Options) TLSConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ClientAuth: o.ClientAuth.TLS(),
+	}
+
+	switch {
+	case o.CertificateFile != "" && o.CertificateKeyFile != "":
+		reloader, err := NewCertificateReloader(o.CertificateFile, o.CertificateKeyFile, DefaultCertificateReloadCheck)
+		if err != nil {
+			return nil, err
+		}
+		config.GetCertificate = reloader.GetCertificate
+	case o.SelfSigned:
+		certificate, err := NewSelfSignedCertificate(o.Domain, DefaultSelfSignedValidity)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{*certificate}
+	case o.ClientAuth != ClientAuthNone:
+		return nil, ErrClientAuthWithoutTLS
+	default:
+		return nil, nil
+	}
+
+	if o.ClientAuth != ClientAuthNone && o.ClientCAFile == "" {
+		return nil, ErrClientCARequired
+	}
+
+	if o.ClientCAFile != "" {
+		bundle, err := os.ReadFile(o.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client CA bundle: %w", err)
+		}
+
+		config.ClientCAs = x509.NewCertPool()
+		if !config.ClientCAs.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("%w: %s", ErrClientCANotLoaded, o.ClientCAFile)
+		}
+	}
+
+	return config, nil
+}
+
+// NewSelfSignedCertificate generates an in-memory self-signed certificate for the domain, localhost and the loopback
+// addresses, it is a leaf certificate which cannot sign other certificates.
+func NewSelfSignedCertificate(domain string, validity time.Duration) (*tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: domain, Organization: []string{DefaultVendor}},
+		DNSNames:              []string{domain, "localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  false,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}, nil
+}
+
+// NewClientIdentity returns the identity of the verified client certificate of the request, it is nil when the
+// connection is not TLS or the client certificate is not verified.
+func NewClientIdentity(c *fiber.Ctx) *ClientIdentity {
+	state := c.Context().TLSConnectionState()
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	certificate := state.VerifiedChains[0][0]
+	identity := &ClientIdentity{
+		CommonName:     certificate.Subject.CommonName,
+		Organization:   certificate.Subject.Organization,
+		DNSNames:       certificate.DNSNames,
+		EmailAddresses: certificate.EmailAddresses,
+		URIs:           make([]string, 0, len(certificate.URIs)),
+		SerialNumber:   certificate.SerialNumber.String(),
+		Issuer:         certificate.Issuer.String(),
+	}
+
+	for _, uri := range certificate.URIs {
+		identity.URIs = append(identity.URIs, uri.String())
+	}
+
+	return identity
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertificateReloader(t *testing.T) {
+	directory := t.TempDir()
+	certificateFile := filepath.Join(directory, "tls.crt")
+	keyFile := filepath.Join(directory, "tls.key")
+
+	first, err := NewSelfSignedCertificate("first.leliuga.com", time.Hour)
+	assert.Nil(t, err)
+	writeKeyPair(t, first, certificateFile, keyFile)
+
+	reloader, err := NewCertificateReloader(certificateFile, keyFile, 0)
+	assert.Nil(t, err)
+
+	current, err := reloader.GetCertificate(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, first.Certificate, current.Certificate)
+
+	second, err := NewSelfSignedCertificate("second.leliuga.com", time.Hour)
+	assert.Nil(t, err)
+	writeKeyPair(t, second, certificateFile, keyFile)
+	later := time.Now().Add(time.Minute)
+	assert.Nil(t, os.Chtimes(certificateFile, later, later))
+
+	current, err = reloader.GetCertificate(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, second.Certificate, current.Certificate)
+
+	assert.Nil(t, os.WriteFile(keyFile, []byte("broken"), 0600))
+	assert.Nil(t, os.Chtimes(keyFile, later.Add(time.Minute), later.Add(time.Minute)))
+
+	current, err = reloader.GetCertificate(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, second.Certificate, current.Certificate)
+}
+
+func TestTLSConfig(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	ca, _ := newCertificateAuthority(t)
+	assert.Nil(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}), 0600))
+
+	tests := []struct {
+		tag     string
+		options []Option
+		config  bool
+		err     error
+	}{
+		{"t0", nil, false, nil},
+		{"t1", []Option{WithSelfSigned(true)}, true, nil},
+		{"t2", []Option{WithClientAuth(ClientAuthRequire), WithClientCAFile(caFile)}, false, ErrClientAuthWithoutTLS},
+		{"t3", []Option{WithClientAuth(ClientAuthRequest), WithClientCAFile(caFile)}, false, ErrClientAuthWithoutTLS},
+		{"t4", []Option{WithSelfSigned(true), WithClientAuth(ClientAuthRequire)}, false, ErrClientCARequired},
+		{"t5", []Option{WithSelfSigned(true), WithClientAuth(ClientAuthRequire), WithClientCAFile(caFile)}, true, nil},
+	}
+
+	for _, test := range tests {
+		config, err := NewOptions(test.options...).TLSConfig()
+		assert.ErrorIs(t, err, test.err, test.tag)
+		assert.Equal(t, test.config, config != nil, test.tag)
+	}
+
+	certificate, err := NewSelfSignedCertificate("billing.leliuga.com", time.Hour)
+	assert.Nil(t, err)
+	assert.False(t, certificate.Leaf.IsCA)
+	assert.Zero(t, certificate.Leaf.KeyUsage&x509.KeyUsageCertSign)
+}
+
+func TestServiceMutualTLS(t *testing.T) {
+	ctx := context.Background()
+	directory := t.TempDir()
+	caFile := filepath.Join(directory, "ca.crt")
+
+	ca, caKey := newCertificateAuthority(t)
+	assert.Nil(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}), 0600))
+
+	s := NewService(NewOptions(
+		WithPort(0),
+		WithDisableStartupMessage(true),
+		WithDetectRuntime(false),
+		WithEnvironment(EnvironmentDevelopment),
+		WithSelfSigned(true),
+		WithClientCAFile(caFile),
+		WithClientAuth(ClientAuthRequire),
+	))
+	assert.Nil(t, s.Validate())
+	s.Get("/whoami", func(c *fiber.Ctx) error {
+		return c.SendString(NewClientIdentity(c).CommonName)
+	})
+	assert.Nil(t, s.Start(ctx))
+	defer func() { assert.Nil(t, s.Stop(ctx)) }()
+
+	url := fmt.Sprintf("https://127.0.0.1:%d/whoami", s.Addr().(*net.TCPAddr).Port)
+	config := &tls.Config{InsecureSkipVerify: true}
+
+	_, err := (&http.Client{Transport: &http.Transport{TLSClientConfig: config}}).Get(url)
+	assert.NotNil(t, err)
+
+	config.Certificates = []tls.Certificate{newClientCertificate(t, ca, caKey, "billing")}
+	resp, err := (&http.Client{Transport: &http.Transport{TLSClientConfig: config}}).Get(url)
+	assert.Nil(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, "billing", string(body))
+}
+
+func writeKeyPair(t *testing.T, certificate *tls.Certificate, certificateFile, keyFile string) {
+	key, err := x509.MarshalECPrivateKey(certificate.PrivateKey.(*ecdsa.PrivateKey))
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(certificateFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Certificate[0]}), 0600))
+	assert.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: key}), 0600))
+}
+
+func newCertificateAuthority(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Leliuga CA"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+	ca, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+
+	return ca, key
+}
+
+func newClientCertificate(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, name string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	assert.Nil(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
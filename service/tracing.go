@@ -0,0 +1,75 @@
+package service
+
+import (
+	"github.com/gofiber/fiber/v2"
+	requesttracing "github.com/leliuga/cdk/service/middleware/tracing"
+	"github.com/leliuga/cdk/tracing"
+	"github.com/leliuga/cdk/validation"
+)
+
+// Tracing exporters
+const (
+	TracingExporterStdout = "stdout"
+	TracingExporterFile   = "file"
+	TracingExporterOTLP   = "otlp"
+)
+
+// Default values for the Service tracing
+const (
+	DefaultTracingEnabled    = false
+	DefaultTracingSampleRate = 1.0
+	DefaultTracingExporter   = TracingExporterStdout
+)
+
+// NewTracing creates a new Tracing.
+func NewTracing() *Tracing {
+	return &Tracing{
+		Enabled:    DefaultTracingEnabled,
+		SampleRate: DefaultTracingSampleRate,
+		Exporter:   DefaultTracingExporter,
+		Endpoint:   tracing.DefaultOTLPEndpoint,
+	}
+}
+
+// Exporters returns the configured span exporters, there are none when the tracing is disabled.
+func (t *Tracing) Exporters() ([]tracing.IExporter, error) {
+	if t == nil || !t.Enabled {
+		return []tracing.IExporter{}, nil
+	}
+
+	switch t.Exporter {
+	case TracingExporterFile:
+		exporter, err := tracing.NewFileExporter(t.File)
+		if err != nil {
+			return nil, err
+		}
+
+		return []tracing.IExporter{exporter}, nil
+	case TracingExporterOTLP:
+		return []tracing.IExporter{tracing.NewOTLPExporter(t.Endpoint)}, nil
+	default:
+		return []tracing.IExporter{tracing.NewStdoutExporter()}, nil
+	}
+}
+
+// Handler returns the tracing middleware, it passes the requests through when the tracing is disabled.
+func (t *Tracing) Handler(tracer *tracing.Tracer) fiber.Handler {
+	if t == nil || !t.Enabled {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return requesttracing.New(requesttracing.Config{
+		Tracer: tracer,
+	})
+}
+
+// Validate makes Tracing validatable by implementing [validation.Validatable] interface.
+func (t *Tracing) Validate() error {
+	return validation.ValidateStruct(t,
+		validation.Field(&t.SampleRate, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&t.Exporter, validation.In(TracingExporterStdout, TracingExporterFile, TracingExporterOTLP)),
+		validation.Field(&t.File, validation.When(t.Exporter == TracingExporterFile, validation.Required)),
+	)
+}
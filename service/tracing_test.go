@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	requesttracing "github.com/leliuga/cdk/service/middleware/tracing"
+	"github.com/leliuga/cdk/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracingContext(t *testing.T) {
+	var contexts []context.Context
+
+	s := NewService(NewOptions())
+	s.Use(requesttracing.New(requesttracing.Config{Tracer: s.Tracer}))
+	s.Get("/", func(c *fiber.Ctx) error {
+		contexts = append(contexts, c.UserContext())
+
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	for _, state := range []string{"vendor=first", "vendor=other"} {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(tracing.HeaderTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		req.Header.Set(tracing.HeaderTracestate, state)
+
+		_, err := s.Test(req)
+		assert.Nil(t, err)
+	}
+
+	assert.Len(t, contexts, 2)
+
+	outgoing := http.Header{}
+	tracing.Inject(contexts[0], outgoing)
+	assert.Equal(t, "vendor=first", outgoing.Get(tracing.HeaderTracestate))
+}
@@ -3,11 +3,23 @@ package service
 
 import (
 	"context"
+	"crypto/tls"
+	"net"
+	nethttp "net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/leliuga/cdk/database"
+	"github.com/leliuga/cdk/http"
+	"github.com/leliuga/cdk/http/openapi"
+	"github.com/leliuga/cdk/http/schema"
+	"github.com/leliuga/cdk/metrics"
+	"github.com/leliuga/cdk/service/middleware/ratelimit"
+	"github.com/leliuga/cdk/tracing"
 	"github.com/leliuga/cdk/types"
+	"github.com/leliuga/cdk/validation"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -16,17 +28,37 @@ type (
 	Service struct {
 		*Options
 		*fiber.App
+		Admin   *fiber.App
+		Health  *Health
+		Metrics *metrics.Registry
+		Tracer  *tracing.Tracer
+
+		listener     net.Listener
+		errs         chan error
+		cancel       context.CancelFunc
+		booted       bool
+		descriptions types.Map[*Description]
+		current      *atomic.Pointer[Options]
+		reload       *sync.Mutex
+		document     *atomic.Pointer[openapi.Document]
 	}
 
 	// Options represents the service options.
 	Options struct {
 		Name                    string                        `json:"name"`
 		Description             string                        `json:"description"`
+		Environment             Environment                   `json:"environment"                env:"ENVIRONMENT"`
 		Port                    int32                         `json:"port"                       env:"PORT"`
+		AdminPort               int32                         `json:"admin_port"                 env:"ADMIN_PORT"`
 		Network                 string                        `json:"network"`
+		ListenAddress           string                        `json:"listen_address"             env:"LISTEN_ADDRESS"`
+		SocketMode              string                        `json:"socket_mode"                env:"SOCKET_MODE"`
 		Domain                  string                        `json:"domain"                     env:"DOMAIN"`
 		CertificateFile         string                        `json:"certificate_file"           env:"CERTIFICATE_FILE"`
 		CertificateKeyFile      string                        `json:"certificate_key_file"       env:"CERTIFICATE_KEY_FILE"`
+		ClientCAFile            string                        `json:"client_ca_file"             env:"CLIENT_CA_FILE"`
+		ClientAuth              ClientAuth                    `json:"client_auth"                env:"CLIENT_AUTH"`
+		SelfSigned              bool                          `json:"self_signed"                env:"SELF_SIGNED"`
 		Views                   fiber.Views                   `json:"-"`
 		BodyLimit               int                           `json:"body_limit"                 env:"BODY_LIMIT"`
 		Concurrency             int                           `json:"concurrency"                env:"CONCURRENCY"`
@@ -34,28 +66,90 @@ type (
 		WriteTimeout            time.Duration                 `json:"write_timeout"              env:"WRITE_TIMEOUT"`
 		IdleTimeout             time.Duration                 `json:"idle_timeout"               env:"IDLE_TIMEOUT"`
 		ShutdownTimeout         time.Duration                 `json:"shutdown_timeout"           env:"SHUTDOWN_TIMEOUT"`
+		ShutdownDelay           time.Duration                 `json:"shutdown_delay"             env:"SHUTDOWN_DELAY"`
 		ReadBufferSize          int                           `json:"read_buffer_size"           env:"READ_BUFFER_SIZE"`
 		WriteBufferSize         int                           `json:"write_buffer_size"          env:"WRITE_BUFFER_SIZE"`
 		EnableTrustedProxyCheck bool                          `json:"enable_trusted_proxy_check" env:"ENABLE_TRUSTED_PROXY_CHECK"`
 		TrustedProxies          []string                      `json:"trusted_proxies"            env:"TRUSTED_PROXIES"`
 		DisableStartupMessage   bool                          `json:"disable_startup_message"    env:"DISABLE_STARTUP_MESSAGE"`
 		EnablePrintRoutes       bool                          `json:"enable_print_routes"        env:"ENABLE_PRINT_ROUTES"`
+		VerboseErrors           bool                          `json:"verbose_errors"             env:"VERBOSE_ERRORS"`
 		BuildInfo               *BuildInfo                    `json:"build_info"`
 		Runtime                 *Runtime                      `json:"runtime"                    env:"RUNTIME"`
+		DetectRuntime           bool                          `json:"detect_runtime"             env:"DETECT_RUNTIME"`
+		RuntimeDetector         *RuntimeDetector              `json:"-"`
 		Database                *database.Options             `json:"database"                   env:"DATABASE"`
+		AccessLog               *AccessLog                    `json:"access_log"                 env:"ACCESS_LOG"`
+		Tracing                 *Tracing                      `json:"tracing"                    env:"TRACING"`
+		RateLimit               *RateLimit                    `json:"rate_limit"                 env:"RATE_LIMIT"`
+		Security                *Security                     `json:"security"                   env:"SECURITY"`
+		Cache                   *Cache                        `json:"cache"                      env:"CACHE"`
+		Idempotency             *Idempotency                  `json:"idempotency"                env:"IDEMPOTENCY"`
+		Middlewares             []string                      `json:"middlewares"                env:"MIDDLEWARES"`
 		ErrorHandler            func(*fiber.Ctx, error) error `json:"-"`
+		Redaction               RedactionPolicy               `json:"-"`
 		Kernel                  IKernel                       `json:"-"`
+		ConfigFile              string                        `json:"-"`
+		ReloadInterval          time.Duration                 `json:"reload_interval"            env:"RELOAD_INTERVAL"`
+		Provenance              types.Map[Source]             `json:"-"`
+
+		loader func() (*Options, error)
+		flags  types.Map[string]
+		err    error
+	}
+
+	// CertificateReloader loads the key pair and reloads it when the files change.
+	CertificateReloader struct {
+		certificateFile string
+		keyFile         string
+		interval        time.Duration
+		certificate     *tls.Certificate
+		modified        time.Time
+		checked         time.Time
+		mutex           *sync.Mutex
 	}
 
+	// ClientIdentity represents the identity of a verified client certificate.
+	ClientIdentity struct {
+		CommonName     string   `json:"common_name"`
+		Organization   []string `json:"organization"`
+		DNSNames       []string `json:"dns_names"`
+		EmailAddresses []string `json:"email_addresses"`
+		URIs           []string `json:"uris"`
+		SerialNumber   string   `json:"serial_number"`
+		Issuer         string   `json:"issuer"`
+	}
+
+	// ConfigChange represents a changed value of the options.
+	ConfigChange struct {
+		Path     string `json:"path"`
+		Previous any    `json:"previous"`
+		Next     any    `json:"next"`
+	}
+
+	// ConfigDiff represents the changes between two options, sorted by path.
+	ConfigDiff []*ConfigChange
+
 	// BuildInfo defines the build information for a Service.
 	BuildInfo struct {
-		Repository   string `json:"repository"`
-		Commit       string `json:"commit"`
-		When         string `json:"when"`
-		GoVersion    string `json:"go_version"`
-		Platform     string `json:"platform"`
-		OS           string `json:"os"`
-		Architecture string `json:"architecture"`
+		Repository   string             `json:"repository"`
+		Commit       string             `json:"commit"`
+		When         string             `json:"when"`
+		Modified     bool               `json:"modified"`
+		GoVersion    string             `json:"go_version"`
+		Platform     string             `json:"platform"`
+		OS           string             `json:"os"`
+		Architecture string             `json:"architecture"`
+		Dependencies []*BuildDependency `json:"dependencies"`
+		Settings     types.Map[string]  `json:"settings"`
+	}
+
+	// BuildDependency defines a module dependency which is compiled into a Service.
+	BuildDependency struct {
+		Path    string `json:"path"`
+		Version string `json:"version"`
+		Sum     string `json:"sum,omitempty"`
+		Replace string `json:"replace,omitempty"`
 	}
 
 	// Runtime defines the runtime for a Service.
@@ -71,6 +165,162 @@ type (
 		Probe              *RuntimeProbe         `json:"probe"                env:"PROBE"`
 	}
 
+	// AccessLog defines the access log of a Service.
+	AccessLog struct {
+		Enabled    bool     `json:"enabled"     env:"ENABLED"`
+		SampleRate float64  `json:"sample_rate" env:"SAMPLE_RATE"`
+		Exclude    []string `json:"exclude"     env:"EXCLUDE"`
+		JSON       bool     `json:"json"        env:"JSON"`
+	}
+
+	// Tracing defines the tracing of a Service.
+	Tracing struct {
+		Enabled    bool    `json:"enabled"     env:"ENABLED"`
+		SampleRate float64 `json:"sample_rate" env:"SAMPLE_RATE"`
+		Exporter   string  `json:"exporter"    env:"EXPORTER"`
+		File       string  `json:"file"        env:"FILE"`
+		Endpoint   string  `json:"endpoint"    env:"ENDPOINT"`
+	}
+
+	// Security defines the security headers and the CORS policy of a Service.
+	Security struct {
+		Enabled                         bool   `json:"enabled"                             env:"ENABLED"`
+		CORS                            *CORS  `json:"cors"                                env:"CORS"`
+		HSTS                            *HSTS  `json:"hsts"                                env:"HSTS"`
+		ContentSecurityPolicy           string `json:"content_security_policy"             env:"CONTENT_SECURITY_POLICY"`
+		ContentSecurityPolicyReportOnly bool   `json:"content_security_policy_report_only" env:"CONTENT_SECURITY_POLICY_REPORT_ONLY"`
+		FrameOptions                    string `json:"frame_options"                       env:"FRAME_OPTIONS"`
+		ReferrerPolicy                  string `json:"referrer_policy"                     env:"REFERRER_POLICY"`
+		PermissionsPolicy               string `json:"permissions_policy"                  env:"PERMISSIONS_POLICY"`
+		ContentTypeNosniff              bool   `json:"content_type_nosniff"                env:"CONTENT_TYPE_NOSNIFF"`
+	}
+
+	// CORS defines the cross-origin resource sharing policy of a Service.
+	CORS struct {
+		Enabled          bool          `json:"enabled"           env:"ENABLED"`
+		AllowOrigins     []string      `json:"allow_origins"     env:"ALLOW_ORIGINS"`
+		AllowMethods     []string      `json:"allow_methods"     env:"ALLOW_METHODS"`
+		AllowHeaders     []string      `json:"allow_headers"     env:"ALLOW_HEADERS"`
+		ExposeHeaders    []string      `json:"expose_headers"    env:"EXPOSE_HEADERS"`
+		AllowCredentials bool          `json:"allow_credentials" env:"ALLOW_CREDENTIALS"`
+		MaxAge           time.Duration `json:"max_age"           env:"MAX_AGE"`
+	}
+
+	// HSTS defines the HTTP strict transport security of a Service.
+	HSTS struct {
+		MaxAge            time.Duration `json:"max_age"            env:"MAX_AGE"`
+		IncludeSubdomains bool          `json:"include_subdomains" env:"INCLUDE_SUBDOMAINS"`
+		Preload           bool          `json:"preload"            env:"PRELOAD"`
+	}
+
+	// RateLimit defines the inbound rate limiting of a Service, the routes override the default policy.
+	RateLimit struct {
+		Enabled      bool                        `json:"enabled"   env:"ENABLED"`
+		Default      *RateLimitPolicy            `json:"default"   env:"DEFAULT"`
+		Exclude      []string                    `json:"exclude"   env:"EXCLUDE"`
+		Routes       types.Map[*RateLimitPolicy] `json:"routes"`
+		KeyGenerator func(c *fiber.Ctx) string   `json:"-"`
+		Storage      database.IKeyValue          `json:"-"`
+	}
+
+	// RateLimitPolicy defines the rate limit of the requests, the empty values of a route fall back to the default policy.
+	RateLimitPolicy struct {
+		Algorithm ratelimit.Algorithm `json:"algorithm" env:"ALGORITHM"`
+		Limit     int                 `json:"limit"     env:"LIMIT"`
+		Window    time.Duration       `json:"window"    env:"WINDOW"`
+		Key       string              `json:"key"       env:"KEY"`
+		Header    string              `json:"header"    env:"HEADER"`
+	}
+
+	// Cache defines the response cache of a Service, the routes override the expiration and 0 disables the cache.
+	Cache struct {
+		Enabled    bool                     `json:"enabled"    env:"ENABLED"`
+		Expiration time.Duration            `json:"expiration" env:"EXPIRATION"`
+		Exclude    []string                 `json:"exclude"    env:"EXCLUDE"`
+		Routes     types.Map[time.Duration] `json:"routes"`
+		Storage    database.IKeyValue       `json:"-"`
+	}
+
+	// Idempotency defines the replay of the mutating requests by their Idempotency-Key, the routes override the
+	// retention and 0 disables the replay.
+	Idempotency struct {
+		Enabled      bool                      `json:"enabled"   env:"ENABLED"`
+		Retention    time.Duration             `json:"retention" env:"RETENTION"`
+		Exclude      []string                  `json:"exclude"   env:"EXCLUDE"`
+		Routes       types.Map[time.Duration]  `json:"routes"`
+		Storage      database.IKeyValue        `json:"-"`
+		KeyGenerator func(c *fiber.Ctx) string `json:"-"`
+	}
+
+	// BindError represents a request which cannot be bound, Errors holds the invalid values by their field names.
+	BindError struct {
+		Status  http.Status       `json:"status"`
+		Message string            `json:"message"`
+		Errors  validation.Errors `json:"errors,omitempty"`
+	}
+
+	// Description represents the documentation of a route of a Service, the request and response are values of their
+	// types.
+	Description struct {
+		Endpoint *schema.Endpoint
+		Request  any
+		Response any
+	}
+
+	// Problem represents the details of an error of a request, see RFC 7807.
+	Problem struct {
+		Type      string            `json:"type"                 xml:"type"`
+		Title     string            `json:"title"                xml:"title"`
+		Status    int               `json:"status"               xml:"status"`
+		Detail    string            `json:"detail,omitempty"     xml:"detail,omitempty"`
+		Instance  string            `json:"instance,omitempty"   xml:"instance,omitempty"`
+		RequestID string            `json:"request_id,omitempty" xml:"request_id,omitempty"`
+		Errors    validation.Errors `json:"errors,omitempty"     xml:"-"`
+	}
+
+	// ProblemField represents an invalid field of a Problem.
+	ProblemField struct {
+		Name    string `json:"name"    xml:"name,attr"`
+		Message string `json:"message" xml:",chardata"`
+	}
+
+	// RuntimeDetector detects the runtime of a Service from the execution environment.
+	RuntimeDetector struct {
+		NamespaceFile   string           `json:"namespace_file"`
+		CgroupDirectory string           `json:"cgroup_directory"`
+		Timeout         time.Duration    `json:"timeout"`
+		Probes          []IMetadataProbe `json:"-"`
+	}
+
+	// RuntimeDetection represents the detected runtime values, the values which are not detected are empty.
+	RuntimeDetection struct {
+		Provider  Provider            `json:"provider"`
+		Region    string              `json:"region"`
+		Zone      string              `json:"zone"`
+		Namespace string              `json:"namespace"`
+		Limits    corev1.ResourceList `json:"limits"`
+	}
+
+	// AwsMetadataProbe detects the placement from the Amazon EC2 instance metadata service.
+	AwsMetadataProbe struct {
+		BaseURL string
+	}
+
+	// GcpMetadataProbe detects the placement from the Google Compute Engine metadata server.
+	GcpMetadataProbe struct {
+		BaseURL string
+	}
+
+	// AzureMetadataProbe detects the placement from the Azure instance metadata service.
+	AzureMetadataProbe struct {
+		BaseURL string
+	}
+
+	// DoMetadataProbe detects the placement from the Digital Ocean droplet metadata service.
+	DoMetadataProbe struct {
+		BaseURL string
+	}
+
 	// ResourceRequirements defines the resource requirements for a Service.
 	ResourceRequirements struct {
 		Limits   corev1.ResourceList `json:"limits"   env:"LIMITS"`
@@ -90,12 +340,127 @@ type (
 	Kernel struct {
 		IKernel
 
-		instances types.Map[any]
+		instances   types.Map[any]
+		definitions types.Map[*Definition]
+		components  []*component
+		booted      []*component
+		mutex       *sync.Mutex
+		registry    *sync.RWMutex
 	}
 
+	// Definition represents a lazy factory of a kernel dependency.
+	Definition struct {
+		Scope   Scope
+		Factory func(ctx context.Context, k IKernel) (any, error)
+
+		owner *resolution
+		done  chan struct{}
+	}
+
+	// resolution represents a lookup of a singleton, it records what the lookup waits for to detect the cycles between
+	// concurrent lookups.
+	resolution struct {
+		parent  *resolution
+		waiting *Definition
+	}
+
+	// RequestScope holds the request scoped dependencies of a single request.
+	RequestScope struct {
+		instances map[string]any
+		mutex     *sync.Mutex
+	}
+
+	// component represents a kernel component with its dependencies.
+	component struct {
+		name         string
+		component    IComponent
+		dependencies []string
+	}
+
+	// ComponentError represents an error of a kernel component.
+	ComponentError struct {
+		Component string
+		Op        string
+		Err       error
+	}
+
+	// Health represents the service health, it holds the named checks per probe.
+	Health struct {
+		timeout time.Duration
+		checks  map[Probe]types.Map[HealthCheck]
+		mutex   *sync.RWMutex
+		ready   *atomic.Bool
+		started *atomic.Bool
+	}
+
+	// HealthCheck defines a named health check, a nil error means healthy.
+	HealthCheck func(ctx context.Context) error
+
+	// HealthReport represents the aggregated result of a probe.
+	HealthReport struct {
+		Probe  Probe                         `json:"probe"`
+		Status HealthStatus                  `json:"status"`
+		Checks types.Map[*HealthCheckResult] `json:"checks"`
+	}
+
+	// HealthCheckResult represents the result of a single health check.
+	HealthCheckResult struct {
+		Status  HealthStatus `json:"status"`
+		Latency string       `json:"latency"`
+		Error   string       `json:"error,omitempty"`
+	}
+
+	// HealthStatus defines the status of a health check.
+	HealthStatus uint8
+
+	// Probe defines the kind of health probe (liveness, readiness, startup).
+	Probe uint8
+
+	// Discovery represents the machine-readable description of a Service.
+	Discovery struct {
+		Name        string            `json:"name"`
+		Description string            `json:"description"`
+		Domain      string            `json:"domain"`
+		Environment Environment       `json:"environment"`
+		BuildInfo   *BuildInfo        `json:"build_info"`
+		Runtime     *DiscoveryRuntime `json:"runtime"`
+		Routes      []*DiscoveryRoute `json:"routes"`
+	}
+
+	// DiscoveryRuntime represents the runtime published by the Service discovery.
+	DiscoveryRuntime struct {
+		Provider  Provider `json:"provider"`
+		Region    string   `json:"region"`
+		Zone      string   `json:"zone"`
+		Namespace string   `json:"namespace"`
+		Engine    Engine   `json:"engine"`
+	}
+
+	// DiscoveryRoute represents a route published by the Service discovery.
+	DiscoveryRoute struct {
+		Path    string   `json:"path"`
+		Name    string   `json:"name,omitempty"`
+		Methods []string `json:"methods"`
+	}
+
+	// MiddlewareConstructor returns the middleware of the given Service.
+	MiddlewareConstructor func(s *Service) fiber.Handler
+
+	// RedactionPolicy returns the value to publish for the given dotted path.
+	RedactionPolicy func(path string, value any) any
+
+	// ClientAuth defines the client certificate verification of a Service.
+	ClientAuth uint8
+
 	// Engine defines the engine for a Service runtime.
 	Engine uint8
 
+	// Source defines the configuration layer which set a value of the options.
+	Source uint8
+
+	// Scope defines the lifetime of a kernel dependency.
+	Scope uint8
+
 	// Environment defines the environment in which the Service is running.
 	Environment uint8
 
@@ -105,6 +470,27 @@ type (
 	// Option represents the service option.
 	Option func(o *Options)
 
+	// IComponent represents a kernel component with a lifecycle.
+	IComponent interface {
+		// Boot the component.
+		Boot(ctx context.Context, s *Service) error
+
+		// Shutdown the component.
+		Shutdown(ctx context.Context) error
+	}
+
+	// IMetadataProbe represents a probe of the metadata endpoint of a cloud provider.
+	IMetadataProbe interface {
+		Provider() Provider
+		Probe(ctx context.Context, client *nethttp.Client) (region, zone string, err error)
+	}
+
+	// IReloadable represents a kernel component which is notified when the options are reloaded.
+	IReloadable interface {
+		// Reload applies the changed options.
+		Reload(ctx context.Context, options *Options, diff ConfigDiff) error
+	}
+
 	// IKernel represents the service kernel interface.
 	IKernel interface {
 		// Boot the kernel.
@@ -125,4 +511,33 @@ type (
 		// Instances returns all instances from the kernel.
 		Instances() types.Map[any]
 	}
+
+	// IComponentKernel represents a kernel which boots components in dependency order, the service boots it with the
+	// start context and reloads it when it also implements IReloadable.
+	IComponentKernel interface {
+		IKernel
+
+		// BootContext boots the kernel with the given context.
+		BootContext(ctx context.Context, s *Service) error
+
+		// Register a component with its dependencies to the kernel.
+		Register(name string, component IComponent, dependencies ...string) error
+
+		// Replace a registered component, it keeps the dependencies of the replaced one.
+		Replace(name string, component IComponent) error
+
+		// Components returns the names of the registered components in boot order.
+		Components() ([]string, error)
+	}
+
+	// IContainer represents a kernel which resolves lazy factories.
+	IContainer interface {
+		IKernel
+
+		// Define a lazy factory to the kernel.
+		Define(name string, definition *Definition) error
+
+		// Lookup an instance from the kernel, the lazy factories are invoked according to their scope.
+		Lookup(ctx context.Context, name string) (any, error)
+	}
 )
@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	spanKey contextKey = iota
+	remoteKey
+)
+
+// ContextWithSpan returns a copy of the context with the span.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey, span)
+}
+
+// SpanFromContext returns the span of the context, it is nil when there is no span.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey).(*Span)
+
+	return span
+}
+
+// ContextWithRemoteSpanContext returns a copy of the context with the span context received from a remote caller.
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey, sc)
+}
+
+// SpanContextFromContext returns the span context of the span or the remote caller in the context.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.Context()
+	}
+
+	sc, _ := ctx.Value(remoteKey).(SpanContext)
+
+	return sc
+}
+
+// Inject sets the trace context of the context to the carrier.
+func Inject(ctx context.Context, carrier ICarrier) {
+	sc := SpanContextFromContext(ctx)
+	if !sc.Validate() {
+		return
+	}
+
+	carrier.Set(HeaderTraceparent, sc.Traceparent())
+	if sc.State != "" {
+		carrier.Set(HeaderTracestate, sc.State)
+	}
+}
+
+// Extract returns a copy of the context with the trace context of the carrier, an invalid one is ignored. The values
+// are copied, the carriers backed by the request buffers can be reused when the context outlives the request.
+func Extract(ctx context.Context, carrier ICarrier) context.Context {
+	sc, err := ParseTraceparent(carrier.Get(HeaderTraceparent))
+	if err != nil {
+		return ctx
+	}
+
+	sc.State = strings.Clone(carrier.Get(HeaderTracestate))
+
+	return ContextWithRemoteSpanContext(ctx, sc)
+}
@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/goccy/go-json"
+	"github.com/leliuga/cdk/event"
+)
+
+// NewWriterExporter creates a new exporter which writes the events as json lines to the writer.
+func NewWriterExporter(writer io.Writer) *WriterExporter {
+	return &WriterExporter{
+		writer: writer,
+		mutex:  &sync.Mutex{},
+	}
+}
+
+// NewStdoutExporter creates a new exporter which writes the events as json lines to the standard output.
+func NewStdoutExporter() *WriterExporter {
+	return NewWriterExporter(os.Stdout)
+}
+
+// NewFileExporter creates a new exporter which appends the events as json lines to the file.
+func NewFileExporter(filename string) (*WriterExporter, error) {
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter := NewWriterExporter(file)
+	exporter.closer = file
+
+	return exporter, nil
+}
+
+// Export writes the event as a json line.
+func (w *WriterExporter) Export(_ context.Context, e *event.Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	_, err = w.writer.Write(append(line, '\n'))
+
+	return err
+}
+
+// Shutdown closes the underlying file.
+func (w *WriterExporter) Shutdown(context.Context) error {
+	if w.closer == nil {
+		return nil
+	}
+
+	return w.closer.Close()
+}
@@ -0,0 +1,211 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/leliuga/cdk/event"
+	"k8s.io/klog/v2"
+)
+
+// Default values for the OTLP/HTTP exporter
+const (
+	DefaultOTLPEndpoint      = "http://localhost:4318"
+	DefaultOTLPPath          = "/v1/traces"
+	DefaultOTLPBatchSize     = 512
+	DefaultOTLPFlushInterval = 5 * time.Second
+	DefaultOTLPTimeout       = 10 * time.Second
+)
+
+// OTLP span kinds
+var (
+	otlpSpanKinds = map[SpanKind]int{
+		SpanKindInternal: 1,
+		SpanKindServer:   2,
+		SpanKindClient:   3,
+	}
+)
+
+// NewOTLPExporter creates a new exporter which sends the spans in batches to the OTLP/HTTP collector
+// at the given endpoint, e.g. http://localhost:4318.
+func NewOTLPExporter(endpoint string) *OTLPExporter {
+	if endpoint == "" {
+		endpoint = DefaultOTLPEndpoint
+	}
+
+	exporter := &OTLPExporter{
+		endpoint: strings.TrimSuffix(endpoint, "/") + DefaultOTLPPath,
+		client:   &http.Client{Timeout: DefaultOTLPTimeout},
+		size:     DefaultOTLPBatchSize,
+		spans:    []*Span{},
+		full:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+		once:     &sync.Once{},
+		mutex:    &sync.Mutex{},
+	}
+
+	go exporter.loop(DefaultOTLPFlushInterval)
+
+	return exporter
+}
+
+// Export buffers the span of the event, the background loop is signaled to send the batch when it is full so the
+// caller never waits for the collector.
+func (o *OTLPExporter) Export(_ context.Context, e *event.Event) error {
+	if e.Kind != event.KindApplicationTrace {
+		return nil
+	}
+
+	span := &Span{}
+	if err := json.Unmarshal(e.Data, span); err != nil {
+		return err
+	}
+
+	o.mutex.Lock()
+	o.spans = append(o.spans, span)
+	full := len(o.spans) >= o.size
+	o.mutex.Unlock()
+
+	if full {
+		select {
+		case o.full <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Flush sends the buffered spans to the collector.
+func (o *OTLPExporter) Flush(ctx context.Context) error {
+	o.mutex.Lock()
+	spans := o.spans
+	o.spans = []*Span{}
+	o.mutex.Unlock()
+
+	if len(spans) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(otlpRequest(spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("the collector responded with status %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// Shutdown stops the periodic flush and sends the buffered spans.
+func (o *OTLPExporter) Shutdown(ctx context.Context) error {
+	o.once.Do(func() {
+		close(o.done)
+	})
+	<-o.stopped
+
+	return o.Flush(ctx)
+}
+
+// loop flushes the buffered spans periodically and when the batch is full until the exporter is shut down.
+func (o *OTLPExporter) loop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	defer close(o.stopped)
+
+	for {
+		select {
+		case <-o.done:
+			return
+		case <-ticker.C:
+		case <-o.full:
+		}
+
+		if err := o.Flush(context.Background()); err != nil && !errors.Is(err, context.Canceled) {
+			klog.ErrorS(err, "failed to export the spans", "endpoint", o.endpoint)
+		}
+	}
+}
+
+// otlpRequest returns the OTLP/HTTP json request of the spans grouped by service.
+func otlpRequest(spans []*Span) map[string]any {
+	services := map[string][]any{}
+	var order []string
+
+	for _, span := range spans {
+		if _, ok := services[span.Service]; !ok {
+			order = append(order, span.Service)
+		}
+
+		attributes := make([]any, 0, len(span.Attributes))
+		for key, value := range span.Attributes {
+			attributes = append(attributes, otlpAttribute(key, value))
+		}
+
+		s := map[string]any{
+			"traceId":           span.TraceID,
+			"spanId":            span.SpanID,
+			"name":              span.Name,
+			"kind":              otlpSpanKinds[span.Kind],
+			"startTimeUnixNano": strconv.FormatInt(span.StartTime.UnixNano(), 10),
+			"endTimeUnixNano":   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+			"attributes":        attributes,
+		}
+		if span.ParentID != "" {
+			s["parentSpanId"] = span.ParentID
+		}
+		if span.Error != "" {
+			s["status"] = map[string]any{"code": 2, "message": span.Error}
+		}
+
+		services[span.Service] = append(services[span.Service], s)
+	}
+
+	resourceSpans := make([]any, 0, len(order))
+	for _, service := range order {
+		resourceSpans = append(resourceSpans, map[string]any{
+			"resource": map[string]any{
+				"attributes": []any{otlpAttribute("service.name", service)},
+			},
+			"scopeSpans": []any{
+				map[string]any{
+					"scope": map[string]any{"name": "github.com/leliuga/cdk/tracing"},
+					"spans": services[service],
+				},
+			},
+		})
+	}
+
+	return map[string]any{"resourceSpans": resourceSpans}
+}
+
+// otlpAttribute returns the OTLP json attribute of the key and string value.
+func otlpAttribute(key, value string) map[string]any {
+	return map[string]any{
+		"key":   key,
+		"value": map[string]any{"stringValue": value},
+	}
+}
@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/leliuga/cdk/event"
+	"github.com/leliuga/cdk/types"
+	"k8s.io/klog/v2"
+)
+
+// Context returns the span context which is propagated to the children.
+func (s *Span) Context() SpanContext {
+	return s.context
+}
+
+// SetAttribute sets an attribute of the span.
+func (s *Span) SetAttribute(key, value string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.Attributes.Set(key, value)
+}
+
+// SetError records the error of the span.
+func (s *Span) SetError(err error) {
+	if err == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.Error = err.Error()
+}
+
+// End ends the span and exports it when the trace is sampled, the later calls are ignored.
+func (s *Span) End() {
+	s.mutex.Lock()
+	if s.ended {
+		s.mutex.Unlock()
+		return
+	}
+	s.ended = true
+	s.EndTime = time.Now()
+	s.mutex.Unlock()
+
+	if s.tracer == nil || !s.context.Sampled() {
+		return
+	}
+
+	e := s.Event()
+	for _, exporter := range s.tracer.Exporters {
+		if err := exporter.Export(context.Background(), e); err != nil {
+			klog.ErrorS(err, "failed to export the span", "trace_id", s.TraceID, "span_id", s.SpanID)
+		}
+	}
+}
+
+// Event returns the span as an event of the application trace kind.
+func (s *Span) Event() *event.Event {
+	action := event.ActionCreate
+	if s.Error != "" {
+		action = event.ActionError
+	}
+
+	return event.NewEvent(event.NewOptions(
+		event.WithSource(s.Service),
+		event.WithKind(event.KindApplicationTrace),
+		event.WithAction(action),
+		event.WithAttributes(types.Map[string]{
+			"trace_id":  s.TraceID,
+			"span_id":   s.SpanID,
+			"parent_id": s.ParentID,
+			"name":      s.Name,
+			"kind":      s.Kind.String(),
+		}),
+		event.WithJsonData(s),
+		event.WithHappen(types.DateTime{Time: s.StartTime}),
+	))
+}
+
+// Tracer returns the tracer which created the span.
+func (s *Span) Tracer() *Tracer {
+	return s.tracer
+}
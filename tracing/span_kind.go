@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"bytes"
+	"strings"
+)
+
+const (
+	SpanKindInvalid SpanKind = iota //
+	SpanKindInternal
+	SpanKindServer
+	SpanKindClient
+)
+
+var (
+	SpanKindNames = map[SpanKind]string{
+		SpanKindInternal: "internal",
+		SpanKindServer:   "server",
+		SpanKindClient:   "client",
+	}
+)
+
+// String outputs the SpanKind as a string.
+func (k SpanKind) String() string {
+	return SpanKindNames[k]
+}
+
+// MarshalJSON outputs the SpanKind as a json.
+func (k SpanKind) MarshalJSON() ([]byte, error) {
+	if !k.Validate() {
+		return []byte(`""`), nil
+	}
+
+	return []byte(`"` + k.String() + `"`), nil
+}
+
+// UnmarshalJSON parses the SpanKind from json.
+func (k *SpanKind) UnmarshalJSON(data []byte) error {
+	str := string(bytes.Trim(data, `"`))
+	if kind := ParseSpanKind(str); kind.Validate() {
+		*k = kind
+	}
+
+	return nil
+}
+
+// Validate returns true if the SpanKind is valid.
+func (k SpanKind) Validate() bool {
+	return k != SpanKindInvalid
+}
+
+// ParseSpanKind parses the SpanKind from string.
+func ParseSpanKind(value string) SpanKind {
+	value = strings.ToLower(value)
+	for k, v := range SpanKindNames {
+		if v == value {
+			return k
+		}
+	}
+
+	return SpanKindInvalid
+}
@@ -0,0 +1,121 @@
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Header names of the W3C trace context
+const (
+	HeaderTraceparent = "Traceparent"
+	HeaderTracestate  = "Tracestate"
+)
+
+// FlagSampled is the trace flag of the sampled traces.
+const FlagSampled byte = 0x01
+
+var (
+	ErrInvalidTraceparent = errors.New("invalid traceparent")
+)
+
+// ParseTraceparent parses the span context from the traceparent header value.
+func ParseTraceparent(value string) (SpanContext, error) {
+	var sc SpanContext
+
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return sc, fmt.Errorf("%w: %s", ErrInvalidTraceparent, value)
+	}
+
+	version, err := decode(parts[0], 1)
+	if err != nil || version[0] == 0xff {
+		return sc, fmt.Errorf("%w: %s", ErrInvalidTraceparent, value)
+	}
+
+	traceID, err := decode(parts[1], len(sc.TraceID))
+	if err != nil {
+		return sc, fmt.Errorf("%w: %s", ErrInvalidTraceparent, value)
+	}
+
+	spanID, err := decode(parts[2], len(sc.SpanID))
+	if err != nil {
+		return sc, fmt.Errorf("%w: %s", ErrInvalidTraceparent, value)
+	}
+
+	flags, err := decode(parts[3], 1)
+	if err != nil {
+		return sc, fmt.Errorf("%w: %s", ErrInvalidTraceparent, value)
+	}
+
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Flags = flags[0]
+	sc.Remote = true
+
+	if !sc.Validate() {
+		return SpanContext{}, fmt.Errorf("%w: %s", ErrInvalidTraceparent, value)
+	}
+
+	return sc, nil
+}
+
+// Traceparent returns the traceparent header value of the span context.
+func (sc SpanContext) Traceparent() string {
+	return fmt.Sprintf("00-%s-%s-%02x", sc.TraceID, sc.SpanID, sc.Flags)
+}
+
+// Validate returns true if the trace and span identifiers are not zero.
+func (sc SpanContext) Validate() bool {
+	return sc.TraceID.Validate() && sc.SpanID.Validate()
+}
+
+// Sampled returns true if the trace is sampled.
+func (sc SpanContext) Sampled() bool {
+	return sc.Flags&FlagSampled == FlagSampled
+}
+
+// String outputs the TraceID as a lower case hex string.
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// Validate returns true if the TraceID is not zero.
+func (t TraceID) Validate() bool {
+	return t != TraceID{}
+}
+
+// String outputs the SpanID as a lower case hex string.
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// Validate returns true if the SpanID is not zero.
+func (s SpanID) Validate() bool {
+	return s != SpanID{}
+}
+
+// newTraceID returns a new random TraceID.
+func newTraceID() (id TraceID) {
+	_, _ = rand.Read(id[:])
+
+	return id
+}
+
+// newSpanID returns a new random SpanID.
+func newSpanID() (id SpanID) {
+	_, _ = rand.Read(id[:])
+
+	return id
+}
+
+// decode decodes the lower case hex value of the given length in bytes.
+func decode(value string, length int) ([]byte, error) {
+	if len(value) != length*2 || strings.ToLower(value) != value {
+		return nil, ErrInvalidTraceparent
+	}
+
+	return hex.DecodeString(value)
+}
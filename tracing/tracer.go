@@ -0,0 +1,102 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/leliuga/cdk/types"
+)
+
+// Default values for the tracer
+const (
+	DefaultSampleRate = 1.0
+)
+
+// NewTracer creates a new tracer.
+func NewTracer(options ...Option) *Tracer {
+	opts := Options{
+		SampleRate: DefaultSampleRate,
+		Exporters:  []IExporter{},
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &Tracer{Options: &opts}
+}
+
+// Start starts a new span, it is a child of the span or the remote span context in the given context.
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	parent := SpanContextFromContext(ctx)
+	sc := SpanContext{
+		TraceID: parent.TraceID,
+		SpanID:  newSpanID(),
+		Flags:   parent.Flags,
+		State:   parent.State,
+	}
+
+	if !parent.Validate() {
+		sc.TraceID = newTraceID()
+		sc.Flags = 0
+		if rand.Float64() < t.SampleRate {
+			sc.Flags = FlagSampled
+		}
+	}
+
+	span := &Span{
+		Name:       name,
+		Kind:       kind,
+		Service:    t.Service,
+		TraceID:    sc.TraceID.String(),
+		SpanID:     sc.SpanID.String(),
+		StartTime:  time.Now(),
+		Attributes: types.NewMap[string](),
+		context:    sc,
+		tracer:     t,
+		mutex:      &sync.Mutex{},
+	}
+
+	if parent.Validate() {
+		span.ParentID = parent.SpanID.String()
+	}
+
+	return ContextWithSpan(ctx, span), span
+}
+
+// Shutdown shuts down the exporters, the buffered spans are flushed.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	var errs []error
+
+	for _, exporter := range t.Exporters {
+		if err := exporter.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WithService sets the name of the service which creates the spans.
+func WithService(value string) Option {
+	return func(o *Options) {
+		o.Service = value
+	}
+}
+
+// WithSampleRate sets the fraction of the new traces which are sampled.
+func WithSampleRate(value float64) Option {
+	return func(o *Options) {
+		o.SampleRate = value
+	}
+}
+
+// WithExporters sets the exporters of the finished spans.
+func WithExporters(values ...IExporter) Option {
+	return func(o *Options) {
+		o.Exporters = values
+	}
+}
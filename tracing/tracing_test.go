@@ -0,0 +1,135 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/leliuga/cdk/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		tag   string
+		value string
+		valid bool
+	}{
+		{"t0", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"t1", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true},
+		{"t2", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false},
+		{"t3", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"t4", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"t5", "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"t6", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false},
+		{"t7", "", false},
+	}
+
+	for _, test := range tests {
+		sc, err := ParseTraceparent(test.value)
+		if !test.valid {
+			assert.ErrorIs(t, err, ErrInvalidTraceparent, test.tag)
+			continue
+		}
+
+		assert.Nil(t, err, test.tag)
+		assert.True(t, sc.Sampled(), test.tag)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String(), test.tag)
+	}
+}
+
+func TestTracerPropagation(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tracer := NewTracer(WithService("billing"), WithExporters(NewWriterExporter(buf)))
+
+	header := http.Header{}
+	header.Set(HeaderTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	header.Set(HeaderTracestate, "vendor=value")
+
+	ctx, server := tracer.Start(Extract(context.Background(), header), "GET /", SpanKindServer)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", server.ParentID)
+
+	ctx, client := tracer.Start(ctx, "GET billing", SpanKindClient)
+	assert.Equal(t, server.SpanID, client.ParentID)
+
+	outgoing := http.Header{}
+	Inject(ctx, outgoing)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+client.SpanID+"-01", outgoing.Get(HeaderTraceparent))
+	assert.Equal(t, "vendor=value", outgoing.Get(HeaderTracestate))
+
+	client.End()
+	client.End()
+	server.End()
+
+	var e event.Event
+	line, _ := buf.ReadBytes('\n')
+	assert.Nil(t, json.Unmarshal(line, &e))
+	assert.Equal(t, event.KindApplicationTrace, e.Kind)
+	assert.Equal(t, client.SpanID, e.Attributes.Get("span_id"))
+	line, _ = buf.ReadBytes('\n')
+	assert.NotEmpty(t, line)
+	_, err := buf.ReadBytes('\n')
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var received map[string]any
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, DefaultOTLPPath, r.URL.Path)
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer collector.Close()
+
+	exporter := NewOTLPExporter(collector.URL)
+	tracer := NewTracer(WithService("billing"), WithExporters(exporter))
+	_, span := tracer.Start(context.Background(), "job", SpanKindInternal)
+	span.End()
+
+	assert.Nil(t, tracer.Shutdown(context.Background()))
+	assert.Len(t, received["resourceSpans"], 1)
+}
+
+func TestOTLPExporterBatch(t *testing.T) {
+	received := make(chan int, 1)
+	release := make(chan struct{})
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []any `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&request))
+		<-release
+		received <- len(request.ResourceSpans[0].ScopeSpans[0].Spans)
+	}))
+	defer collector.Close()
+
+	exporter := NewOTLPExporter(collector.URL)
+	exporter.size = 2
+	tracer := NewTracer(WithService("billing"), WithExporters(exporter))
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		_, span := tracer.Start(context.Background(), "job", SpanKindInternal)
+		span.End()
+	}
+	assert.Less(t, time.Since(start), time.Second)
+
+	close(release)
+	select {
+	case count := <-received:
+		assert.Equal(t, 2, count)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the full batch was not sent")
+	}
+
+	assert.Nil(t, tracer.Shutdown(context.Background()))
+}
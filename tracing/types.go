@@ -0,0 +1,102 @@
+// Package tracing provides the W3C trace context propagation and spans exported as events.
+package tracing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/leliuga/cdk/event"
+	"github.com/leliuga/cdk/types"
+)
+
+type (
+	// Tracer creates the spans and exports them when they end.
+	Tracer struct {
+		*Options
+	}
+
+	// Options represents the tracer options.
+	Options struct {
+		Service    string      `json:"service"`
+		SampleRate float64     `json:"sample_rate"`
+		Exporters  []IExporter `json:"-"`
+	}
+
+	// Span represents a timed operation of a trace.
+	Span struct {
+		Name       string            `json:"name"`
+		Kind       SpanKind          `json:"kind"`
+		Service    string            `json:"service"`
+		TraceID    string            `json:"trace_id"`
+		SpanID     string            `json:"span_id"`
+		ParentID   string            `json:"parent_id,omitempty"`
+		StartTime  time.Time         `json:"start_time"`
+		EndTime    time.Time         `json:"end_time"`
+		Attributes types.Map[string] `json:"attributes"`
+		Error      string            `json:"error,omitempty"`
+
+		context SpanContext
+		tracer  *Tracer
+		ended   bool
+		mutex   *sync.Mutex
+	}
+
+	// SpanContext represents the propagated part of a span.
+	SpanContext struct {
+		TraceID TraceID
+		SpanID  SpanID
+		Flags   byte
+		State   string
+		Remote  bool
+	}
+
+	// TraceID represents the identifier of a trace.
+	TraceID [16]byte
+
+	// SpanID represents the identifier of a span.
+	SpanID [8]byte
+
+	// WriterExporter exports the events as json lines to a writer.
+	WriterExporter struct {
+		writer io.Writer
+		closer io.Closer
+		mutex  *sync.Mutex
+	}
+
+	// OTLPExporter exports the spans in batches to an OTLP/HTTP collector with the json encoding.
+	OTLPExporter struct {
+		endpoint string
+		client   *http.Client
+		size     int
+		spans    []*Span
+		full     chan struct{}
+		done     chan struct{}
+		stopped  chan struct{}
+		once     *sync.Once
+		mutex    *sync.Mutex
+	}
+
+	// IExporter represents an exporter of the finished spans.
+	IExporter interface {
+		Export(ctx context.Context, e *event.Event) error
+		Shutdown(ctx context.Context) error
+	}
+
+	// SpanKind represents the role of a span.
+	SpanKind uint8
+
+	// Option represents the tracer option.
+	Option func(o *Options)
+
+	// ICarrier represents the headers which carry the trace context.
+	ICarrier interface {
+		Get(key string) string
+		Set(key, value string)
+	}
+
+	// contextKey represents a key of the tracing values in the context.
+	contextKey uint8
+)
@@ -109,7 +109,7 @@ func (m Map[T]) Range(fn func(key string, value T) bool) {
 func (m Map[T]) String(sep, join string) string {
 	parts := make([]string, 0, m.Len())
 	for key, value := range m {
-		parts = append(parts, fmt.Sprintf("%s%s%s", key, sep, value))
+		parts = append(parts, fmt.Sprintf("%s%s%s", key, sep, any(value)))
 	}
 
 	sort.Strings(parts)
@@ -126,17 +126,17 @@ func (m Map[T]) Value() (driver.Value, error) {
 func (m Map[T]) AsString(key string) string {
 	switch reflect.ValueOf(m[key]).Kind() {
 	case reflect.Bool:
-		return fmt.Sprintf("%t", m[key])
+		return fmt.Sprintf("%t", any(m[key]))
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return fmt.Sprintf("%d", m[key])
+		return fmt.Sprintf("%d", any(m[key]))
 	case reflect.Float32, reflect.Float64:
-		return fmt.Sprintf("%f", m[key])
+		return fmt.Sprintf("%f", any(m[key]))
 	case reflect.Invalid:
 		return ""
 	}
 
-	return fmt.Sprintf("%s", m[key])
+	return fmt.Sprintf("%s", any(m[key]))
 }
 
 // Merge merges the provided maps into a new map.
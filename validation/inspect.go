@@ -0,0 +1,76 @@
+package validation
+
+import (
+	"reflect"
+)
+
+type (
+	// Constraints represents the rules of a struct field, e.g. to describe the field in a schema.
+	Constraints struct {
+		Required     bool
+		MinLength    int
+		MaxLength    int
+		Min          any
+		Max          any
+		ExclusiveMin bool
+		ExclusiveMax bool
+		Enum         []any
+		Pattern      string
+	}
+
+	// Inspectable is the interface indicating the struct declares the rules of its fields, they are described without
+	// validating the struct. Rules must have a pointer receiver so the rules point to the fields of the struct.
+	Inspectable interface {
+		// Rules returns the rules of the fields of the struct.
+		Rules() []*FieldRules
+	}
+)
+
+// Inspect returns the constraints of the struct fields by their error field names, they are declared by the Rules
+// method of the struct pointer. The rules of the conditions and of the nested structs are not inspected.
+func Inspect(value Inspectable) map[string]*Constraints {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	sv := rv.Elem()
+	constraints := map[string]*Constraints{}
+	for _, fr := range value.Rules() {
+		fv := reflect.ValueOf(fr.fieldPtr)
+		if fv.Kind() != reflect.Ptr {
+			continue
+		}
+
+		if ft := findStructField(sv, fv); ft != nil && !ft.Anonymous {
+			c := &Constraints{}
+			for _, rule := range fr.rules {
+				c.add(rule)
+			}
+			constraints[getErrorFieldName(ft)] = c
+		}
+	}
+
+	return constraints
+}
+
+// add adds the constraint of the rule, the rules which cannot be described are ignored.
+func (c *Constraints) add(rule Rule) {
+	switch r := rule.(type) {
+	case RequiredRule:
+		c.Required = c.Required || r.condition && !r.skipNil
+	case LengthRule:
+		c.MinLength, c.MaxLength = r.min, r.max
+	case ThresholdRule:
+		switch r.operator {
+		case greaterThan, greaterEqualThan:
+			c.Min, c.ExclusiveMin = r.threshold, r.operator == greaterThan
+		case lessThan, lessEqualThan:
+			c.Max, c.ExclusiveMax = r.threshold, r.operator == lessThan
+		}
+	case InRule:
+		c.Enum = r.elements
+	case MatchRule:
+		c.Pattern = r.re.String()
+	}
+}
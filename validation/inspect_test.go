@@ -0,0 +1,56 @@
+package validation
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type inspected struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Amount int    `json:"amount"`
+	Ratio  float64
+	Note   string `json:"note"`
+}
+
+func (i *inspected) Rules() []*FieldRules {
+	return []*FieldRules{
+		Field(&i.Name, Required, Length(3, 10), Match(regexp.MustCompile("^[a-z]+$"))),
+		Field(&i.Kind, In("a", "b")),
+		Field(&i.Amount, Min(1), Max(100).Exclusive()),
+		Field(&i.Ratio, Min(0.5).Exclusive()),
+		Field(&i.Note, When(true, Required), NilOrNotEmpty),
+	}
+}
+
+func (i inspected) Validate() error {
+	return ValidateStruct(&i, i.Rules()...)
+}
+
+func TestInspect(t *testing.T) {
+	tests := []struct {
+		tag         string
+		field       string
+		constraints *Constraints
+	}{
+		{"t0", "name", &Constraints{Required: true, MinLength: 3, MaxLength: 10, Pattern: "^[a-z]+$"}},
+		{"t1", "kind", &Constraints{Enum: []any{"a", "b"}}},
+		{"t2", "amount", &Constraints{Min: 1, Max: 100, ExclusiveMax: true}},
+		{"t3", "Ratio", &Constraints{Min: 0.5, ExclusiveMin: true}},
+		{"t4", "note", &Constraints{}},
+	}
+
+	constraints := Inspect(&inspected{Name: "invalid name"})
+	assert.Len(t, constraints, len(tests))
+
+	for _, test := range tests {
+		assert.Equal(t, test.constraints, constraints[test.field], test.tag)
+	}
+
+	assert.NotNil(t, inspected{}.Validate())
+
+	var nilPtr *inspected
+	assert.Nil(t, Inspect(nilPtr))
+}